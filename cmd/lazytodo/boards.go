@@ -0,0 +1,45 @@
+package main
+
+import (
+	"github.com/hungtrd/lazytodo/internal/repository"
+	"github.com/hungtrd/lazytodo/internal/repository/fs"
+)
+
+// boardRepositories opens named boards below the data directory with the
+// configured backend. Stores stay open, and cached, until close.
+type boardRepositories struct {
+	dataDir string
+	cfg     repository.Config
+
+	open    map[string]watchableRepository
+	closers []func()
+}
+
+func newBoardRepositories(dataDir string, cfg repository.Config) *boardRepositories {
+	return &boardRepositories{dataDir: dataDir, cfg: cfg, open: map[string]watchableRepository{}}
+}
+
+func (b *boardRepositories) Open(name string) (repository.TaskRepository, error) {
+	return b.openWatchable(name)
+}
+
+func (b *boardRepositories) openWatchable(name string) (watchableRepository, error) {
+	if repo, ok := b.open[name]; ok {
+		return repo, nil
+	}
+	repo, closeRepo, err := openTaskRepository(fs.BoardDir(b.dataDir, name), b.cfg)
+	if err != nil {
+		return nil, err
+	}
+	b.open[name] = repo
+	b.closers = append(b.closers, closeRepo)
+	return repo, nil
+}
+
+func (b *boardRepositories) close() {
+	for _, c := range b.closers {
+		c()
+	}
+}
+
+var _ repository.BoardRepository = (*boardRepositories)(nil)
@@ -1,20 +1,156 @@
 package main
 
 import (
+    "errors"
+    "flag"
     "fmt"
     "os"
+    "path/filepath"
 
+    "github.com/hungtrd/lazytodo/internal/cli"
+    "github.com/hungtrd/lazytodo/internal/repository"
     "github.com/hungtrd/lazytodo/internal/repository/fs"
+    "github.com/hungtrd/lazytodo/internal/repository/sqlite"
     "github.com/hungtrd/lazytodo/internal/task"
     "github.com/hungtrd/lazytodo/internal/ui"
+    "github.com/hungtrd/lazytodo/internal/watch"
 )
 
+// watchableRepository is a task repository backed by a single file.
+type watchableRepository interface {
+    repository.TaskRepository
+    Path() (string, error)
+}
+
+// exitCode ends the process with a status a subcommand already reported.
+type exitCode int
+
+func (c exitCode) Error() string { return fmt.Sprintf("exit status %d", int(c)) }
+
 func main() {
-    taskRepo := fs.NewTaskStore()
-    cfgRepo := fs.NewConfigStore()
-    svc := task.NewService(taskRepo, cfgRepo)
-    if err := ui.Run(svc); err != nil {
+    if err := run(); err != nil {
+        var code exitCode
+        if errors.As(err, &code) {
+            os.Exit(int(code))
+        }
         fmt.Fprintf(os.Stderr, "error: %v\n", err)
         os.Exit(1)
     }
 }
+
+func run() error {
+    dataDir := flag.String("data-dir", "", "directory for tasks and config (default: $"+fs.EnvHome+", XDG dirs or ~/.lazytodo)")
+    global := flag.Bool("global", false, "ignore project boards in the working directory")
+    boardFlag := flag.String("board", "", "named board to open (default: the last one used)")
+    flag.Parse()
+
+    dirs, err := fs.ResolveDirs(*dataDir)
+    if err != nil {
+        return err
+    }
+    cfgRepo := fs.NewConfigStore(dirs.Config)
+    cfg, _ := cfgRepo.Load()
+
+    var taskRepo watchableRepository
+    var boards *boardRepositories
+    boardName := *boardFlag
+    if project, ok := findProjectBoard(dirs, *dataDir != "" || *global || *boardFlag != ""); ok {
+        store := project.Store()
+        if cfg.Backups != 0 {
+            store.SetMaxBackups(cfg.Backups)
+        }
+        taskRepo = store
+        boardName = "project: " + project.Name()
+    } else {
+        if boardName == "" {
+            boardName = cfg.Board
+        }
+        if boardName == "" {
+            boardName = repository.DefaultBoard
+        }
+        if err := task.ValidateBoardName(boardName); err != nil {
+            return err
+        }
+        boards = newBoardRepositories(dirs.Data, cfg)
+        defer boards.close()
+        if taskRepo, err = boards.openWatchable(boardName); err != nil {
+            return err
+        }
+    }
+    svc := task.NewService(taskRepo, cfgRepo)
+    if boards != nil {
+        if err := svc.SetBoards(boards, boardName); err != nil {
+            return err
+        }
+    } else {
+        svc.SetBoardName(boardName)
+    }
+
+    if flag.NArg() > 0 {
+        if _, err := svc.Load(); err != nil {
+            return err
+        }
+        if code := cli.Run(svc, flag.Args(), os.Stdin, os.Stdout, os.Stderr); code != cli.ExitOK {
+            return exitCode(code)
+        }
+        return nil
+    }
+
+    var changes <-chan struct{}
+    if path, err := taskRepo.Path(); err == nil {
+        w := watch.New(path)
+        changes = w.Events()
+        defer w.Close()
+        svc.OnBoardSwitch(func(repo repository.TaskRepository) {
+            if r, ok := repo.(watchableRepository); ok {
+                if path, err := r.Path(); err == nil {
+                    w.SetPath(path)
+                }
+            }
+        })
+    }
+    return ui.Run(svc, changes)
+}
+
+// findProjectBoard looks for a .lazytodo/ or .lazytodo.json board from the
+// working directory up. An explicit --data-dir or --global disables the
+// lookup; the global data dir itself never counts as a project board.
+func findProjectBoard(dirs fs.Dirs, disabled bool) (fs.ProjectBoard, bool) {
+    if disabled {
+        return fs.ProjectBoard{}, false
+    }
+    cwd, err := os.Getwd()
+    if err != nil {
+        return fs.ProjectBoard{}, false
+    }
+    skip := []string{dirs.Data, dirs.Config}
+    if legacy, err := fs.LegacyDir(); err == nil {
+        skip = append(skip, legacy)
+    }
+    return fs.FindProjectBoard(cwd, skip...)
+}
+
+// openTaskRepository opens the backend selected by cfg.Storage. Switching to
+// SQLite imports the existing tasks.json into the new database once.
+func openTaskRepository(dir string, cfg repository.Config) (watchableRepository, func(), error) {
+    jsonRepo := fs.NewTaskStore(dir)
+    if cfg.Backups != 0 {
+        jsonRepo.SetMaxBackups(cfg.Backups)
+    }
+    switch cfg.Storage {
+    case "", repository.StorageJSON:
+        return jsonRepo, func() {}, nil
+    case repository.StorageSQLite:
+        db, err := sqlite.Open(filepath.Join(dir, sqlite.DBFileName))
+        if err != nil {
+            return nil, nil, err
+        }
+        if _, err := db.MigrateFrom(jsonRepo); err != nil {
+            _ = db.Close()
+            return nil, nil, fmt.Errorf("migrate tasks.json: %w", err)
+        }
+        return db, func() { _ = db.Close() }, nil
+    default:
+        return nil, nil, fmt.Errorf("unknown storage %q in config", cfg.Storage)
+    }
+}
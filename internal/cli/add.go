@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+var addCommand = command{
+	name:  "add",
+	usage: "add [--due date] [--tag name]... [--priority p] [--notes text] [--status s] [-q] <text>...",
+	run:   runAdd,
+}
+
+// tagsFlag collects --tag values; each may hold several tags.
+type tagsFlag []string
+
+func (f *tagsFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *tagsFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
+func runAdd(e *env, args []string) error {
+	fs := newFlags(e)
+	due := fs.String("due", "", "due date: 2026-10-23, today, fri, +3d, ...")
+	var tags tagsFlag
+	fs.Var(&tags, "tag", "tag to add; repeat or separate with commas")
+	priority := fs.String("priority", "", "none, low, medium, high or urgent")
+	notes := fs.String("notes", "", "notes in markdown")
+	status := fs.String("status", "todo", "column: todo, doing or done")
+	quiet := fs.Bool("q", false, "print only the short ID of the new task")
+	pos, err := parseFlags(e, fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) == 0 {
+		return usagef("missing task text")
+	}
+
+	// the text is read as in the board, so "due:fri #ops" work here too
+	now := time.Now()
+	edit, err := task.ParseText(strings.Join(pos, " "), now)
+	if err != nil {
+		return err
+	}
+	if *due != "" {
+		d, ok, err := task.ParseDue(*due, now)
+		if err != nil {
+			return err
+		}
+		edit.DueAt = 0
+		if ok {
+			edit.DueAt = d.Unix()
+		}
+	}
+	for _, list := range tags {
+		more, err := task.ParseTags(list)
+		if err != nil {
+			return err
+		}
+		for _, tag := range more {
+			if !slices.Contains(edit.Tags, tag) {
+				edit.Tags = append(edit.Tags, tag)
+			}
+		}
+	}
+	if edit.Priority, err = task.ParsePriority(*priority); err != nil {
+		return err
+	}
+	edit.Notes = strings.TrimRight(*notes, " \t\r\n")
+	st, err := parseStatus(*status)
+	if err != nil {
+		return err
+	}
+
+	t, err := e.svc.Create(edit, st)
+	if err != nil {
+		return err
+	}
+	if *quiet {
+		fmt.Fprintln(e.stdout, shortID(t.Id))
+		return nil
+	}
+	fmt.Fprintf(e.stdout, "added %s: %s\n", shortID(t.Id), t.Content)
+	return nil
+}
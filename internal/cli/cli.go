@@ -0,0 +1,148 @@
+// Package cli implements the non-interactive subcommands of lazytodo.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK    = 0
+	ExitError = 1
+	ExitUsage = 2
+	// ExitNotFound means a task argument matched no task, or several.
+	ExitNotFound = 3
+)
+
+// command is one subcommand. run receives the arguments after its name.
+type command struct {
+	name  string
+	usage string
+	run   func(env *env, args []string) error
+}
+
+// env carries what every command needs.
+type env struct {
+	// cmd is the command being run
+	cmd    command
+	svc    *task.Service
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// usageError marks mistakes in how a command was invoked; Run prints the
+// command's usage and exits with ExitUsage for them.
+type usageError struct{ msg string }
+
+func (e usageError) Error() string { return e.msg }
+
+func usagef(format string, args ...any) error { return usageError{msg: fmt.Sprintf(format, args...)} }
+
+// errHelp is returned after a command printed its help for -h.
+var errHelp = errors.New("help requested")
+
+var commands = []command{
+	addCommand,
+	lsCommand,
+	showCommand,
+	editCommand,
+	startCommand,
+	doneCommand,
+	mvCommand,
+	starCommand,
+	rmCommand,
+	trashCommand,
+	exportCommand,
+	importCommand,
+	importTaskbookCommand,
+}
+
+func lookup(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// Run executes the subcommand named by args[0] and returns the process exit
+// code. The service must already be loaded.
+func Run(svc *task.Service, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return ExitUsage
+	}
+	c, ok := lookup(args[0])
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n", args[0])
+		printUsage(stderr)
+		return ExitUsage
+	}
+	e := &env{cmd: c, svc: svc, stdin: stdin, stdout: stdout, stderr: stderr}
+	if err := c.run(e, args[1:]); err != nil {
+		if errors.Is(err, errHelp) {
+			return ExitOK
+		}
+		if _, ok := err.(notFoundError); ok {
+			fmt.Fprintf(stderr, "%s: %v\n", c.name, err)
+			return ExitNotFound
+		}
+		if _, ok := err.(usageError); ok {
+			fmt.Fprintf(stderr, "%s: %v\nusage: lazytodo %s\n", c.name, err, c.usage)
+			return ExitUsage
+		}
+		fmt.Fprintf(stderr, "%s: %v\n", c.name, err)
+		return ExitError
+	}
+	return ExitOK
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: lazytodo [flags] [command]")
+	fmt.Fprintln(w, "\nwith no command, the interactive board starts. commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %s\n", c.usage)
+	}
+}
+
+// newFlags returns a flag set for the running command. Parse errors are
+// reported by Run, so the set itself prints nothing.
+func newFlags(e *env) *flag.FlagSet {
+	fs := flag.NewFlagSet(e.cmd.name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseFlags parses args with fs and returns the positional arguments.
+// Flags may follow them, as in "done 3 --quiet"; everything after "--" is
+// positional.
+func parseFlags(e *env, fs *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintf(e.stdout, "usage: lazytodo %s\n", e.cmd.usage)
+				fs.SetOutput(e.stdout)
+				fs.PrintDefaults()
+				return nil, errHelp
+			}
+			return nil, usageError{msg: err.Error()}
+		}
+		rest := fs.Args()
+		if n := len(args) - len(rest); n > 0 && args[n-1] == "--" {
+			return append(pos, rest...), nil
+		}
+		if len(rest) == 0 {
+			return pos, nil
+		}
+		pos = append(pos, rest[0])
+		args = rest[1:]
+	}
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+	"github.com/hungtrd/lazytodo/internal/testutil"
+)
+
+// run runs a command and returns its standard output.
+func run(t *testing.T, svc *task.Service, args ...string) string {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	if code := Run(svc, args, strings.NewReader(""), &stdout, &stderr); code != ExitOK {
+		t.Fatalf("%v: exit %d: %s", args, code, stderr.String())
+	}
+	return stdout.String()
+}
+
+func TestNotesKeepIndentation(t *testing.T) {
+	svc := testutil.TempService(t)
+	notes := func() string {
+		t.Helper()
+		todo := svc.Tasks()[domain.TaskStatusTodo]
+		if len(todo) != 1 {
+			t.Fatalf("%d tasks, want 1", len(todo))
+		}
+		return todo[0].Notes
+	}
+
+	run(t, svc, "add", "--notes", "  indented\n- item\n\n", "fix")
+	if got, want := notes(), "  indented\n- item"; got != want {
+		t.Fatalf("add: notes %q, want %q", got, want)
+	}
+	run(t, svc, "edit", "1", "--notes", "    code block\n  - nested\n")
+	if got, want := notes(), "    code block\n  - nested"; got != want {
+		t.Fatalf("edit: notes %q, want %q", got, want)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"success", []string{"done", "1"}, ExitOK, ""},
+		{"help", []string{"add", "-h"}, ExitOK, ""},
+		{"unknown id", []string{"done", "zzz"}, ExitNotFound, `no task matches "zzz"`},
+		{"bad flag", []string{"done", "--bogus", "1"}, ExitUsage, "flag provided but not defined"},
+		{"bad status", []string{"mv", "1", "nowhere"}, ExitUsage, "usage: lazytodo mv"},
+		{"unknown command", []string{"frob"}, ExitUsage, `unknown command "frob"`},
+		{"no command", nil, ExitUsage, "usage: lazytodo"},
+		{"invalid due date", []string{"add", "--due", "zzz", "x"}, ExitError, `invalid due date "zzz"`},
+		{"missing import file", []string{"import", "testdata/missing.json"}, ExitError, "missing.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := testutil.TempService(t)
+			run(t, svc, "add", "fix")
+			var stdout, stderr bytes.Buffer
+			code := Run(svc, tt.args, strings.NewReader(""), &stdout, &stderr)
+			if code != tt.code {
+				t.Fatalf("exit %d, want %d: %s", code, tt.code, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Fatalf("stderr %q, want it to contain %q", stderr.String(), tt.stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/editor"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+var editCommand = command{
+	name:  "edit",
+	usage: "edit <n|id> [--title text] [--tags list] [--due date] [--priority p] [--notes text]",
+	run:   runEdit,
+}
+
+func runEdit(e *env, args []string) error {
+	fs := newFlags(e)
+	title := fs.String("title", "", "new title")
+	tags := fs.String("tags", "", "tags replacing the current ones, separated by commas or spaces")
+	due := fs.String("due", "", "due date, or none to clear it")
+	priority := fs.String("priority", "", "none, low, medium, high or urgent")
+	notes := fs.String("notes", "", "notes replacing the current ones")
+	pos, err := parseFlags(e, fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usagef("edit takes one task")
+	}
+	tasks, err := resolveAll(e, pos)
+	if err != nil {
+		return err
+	}
+	t := tasks[0]
+	if fs.NFlag() == 0 {
+		return editInEditor(e, t)
+	}
+
+	edit := task.TaskEdit{Content: t.Content, Tags: t.Tags, DueAt: t.DueAt, Priority: t.Priority, Notes: t.Notes}
+	now := time.Now()
+	fs.Visit(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		switch f.Name {
+		case "title":
+			edit.Content = strings.Join(strings.Fields(*title), " ")
+		case "tags":
+			edit.Tags, err = task.ParseTags(*tags)
+		case "due":
+			var d time.Time
+			var ok bool
+			d, ok, err = task.ParseDue(*due, now)
+			edit.DueAt = 0
+			if ok {
+				edit.DueAt = d.Unix()
+			}
+		case "priority":
+			edit.Priority, err = task.ParsePriority(*priority)
+		case "notes":
+			edit.Notes = strings.TrimRight(*notes, " \t\r\n")
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if t, err = e.svc.ApplyEdit(t.Id, edit); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stdout, "edited %s: %s\n", shortID(t.Id), t.Content)
+	return nil
+}
+
+// editInEditor opens t in the user's editor, as E does on the board. When
+// the result cannot be applied the file is kept and named in the error.
+func editInEditor(e *env, t domain.Task) error {
+	path, err := editor.TempFile(task.FormatEditFile(t))
+	if err != nil {
+		return err
+	}
+	cmd := editor.Command(path)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("editor: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read edit file: %w", err)
+	}
+	edit, err := task.ParseEditFile(string(data), time.Now())
+	if err == nil {
+		t, err = e.svc.ApplyEdit(t.Id, edit)
+	}
+	if err != nil {
+		return fmt.Errorf("%w (your changes are in %s)", err, path)
+	}
+	os.Remove(path)
+	fmt.Fprintf(e.stdout, "edited %s: %s\n", shortID(t.Id), t.Content)
+	return nil
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+// Output formats of the listing commands.
+const (
+	formatPlain  = "plain"
+	formatTable  = "table"
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+)
+
+// formatFlag adds --format to fs.
+func formatFlag(fs *flag.FlagSet) *string {
+	return fs.String("format", formatPlain, "output format: plain, table, json or ndjson")
+}
+
+func checkFormat(f string) error {
+	switch f {
+	case formatPlain, formatTable, formatJSON, formatNDJSON:
+		return nil
+	}
+	return usagef("unknown format %q: use plain, table, json or ndjson", f)
+}
+
+// numbered is a task with its position in the list it was taken from.
+type numbered struct {
+	pos int
+	t   domain.Task
+}
+
+// writeJSON prints tasks in the json or ndjson schema.
+func writeJSON(e *env, format string, tasks []numbered) error {
+	out := make([]taskOutput, 0, len(tasks))
+	for _, n := range tasks {
+		out = append(out, newTaskOutput(n.pos, n.t))
+	}
+	enc := json.NewEncoder(e.stdout)
+	enc.SetEscapeHTML(false)
+	if format == formatNDJSON {
+		for _, t := range out {
+			if err := enc.Encode(ndjsonTask{Version: schemaVersion, taskOutput: t}); err != nil {
+				return fmt.Errorf("write output: %w", err)
+			}
+		}
+		return nil
+	}
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(taskList{Version: schemaVersion, Tasks: out}); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+// writeTable prints rows as aligned columns under header.
+func writeTable(e *env, header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, r := range rows {
+		fmt.Fprintln(tw, strings.Join(r, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+var taskHeader = []string{"#", "ID", "STATUS", "PRIORITY", "DUE", "TITLE"}
+
+// taskRow is a task as a row of the ls table.
+func taskRow(n numbered) []string {
+	prio, due, title := listFields(n.t)
+	return []string{fmt.Sprint(n.pos), shortID(n.t.Id), statusLabel(n.t.Status), prio, due, title}
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/testutil"
+)
+
+func TestJSONOutputVersion(t *testing.T) {
+	svc := testutil.TempService(t)
+	for _, text := range []string{"one #a", "two"} {
+		if _, err := svc.Add(text); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var list struct {
+		Version int               `json:"version"`
+		Tasks   []json.RawMessage `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(run(t, svc, "ls", "--format", "json")), &list); err != nil {
+		t.Fatal(err)
+	}
+	if list.Version != schemaVersion || len(list.Tasks) != 2 {
+		t.Fatalf("json: version %d with %d tasks, want %d with 2", list.Version, len(list.Tasks), schemaVersion)
+	}
+
+	lines := strings.Split(strings.TrimSpace(run(t, svc, "ls", "--format", "ndjson")), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("ndjson: %d lines, want 2", len(lines))
+	}
+	for _, l := range lines {
+		var obj struct {
+			Version *int   `json:"version"`
+			Title   string `json:"title"`
+		}
+		if err := json.Unmarshal([]byte(l), &obj); err != nil {
+			t.Fatal(err)
+		}
+		if obj.Version == nil || *obj.Version != schemaVersion || obj.Title == "" {
+			t.Fatalf("ndjson line %s: want version %d and a title", l, schemaVersion)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/query"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+var lsCommand = command{
+	name:  "ls",
+	usage: "ls [--all] [--status s] [--tag name] [--query q] [--format f]",
+	run:   runList,
+}
+
+var showCommand = command{
+	name:  "show",
+	usage: "show [--format f] <n|id>...",
+	run:   runShow,
+}
+
+func runList(e *env, args []string) error {
+	fs := newFlags(e)
+	all := fs.Bool("all", false, "include done tasks")
+	status := fs.String("status", "", "only this column: todo, doing or done")
+	tag := fs.String("tag", "", "only tasks with this tag")
+	q := fs.String("query", "", "only tasks matching a filter query, as in the board's F prompt")
+	format := formatFlag(fs)
+	pos, err := parseFlags(e, fs, args)
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usagef("unexpected argument %q", pos[0])
+	}
+	var filters []func(domain.Task) bool
+	switch {
+	case *status != "":
+		st, err := parseStatus(*status)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, func(t domain.Task) bool { return t.Status == st })
+	case !*all:
+		filters = append(filters, func(t domain.Task) bool { return t.Status != domain.TaskStatusDone })
+	}
+	if *tag != "" {
+		want := strings.ToLower(strings.TrimPrefix(*tag, "#"))
+		filters = append(filters, func(t domain.Task) bool { return slices.Contains(t.Tags, want) })
+	}
+	if *q != "" {
+		parsed, err := query.Parse(*q)
+		if err != nil {
+			return usagef("query: %v", err)
+		}
+		now := time.Now()
+		filters = append(filters, func(t domain.Task) bool { return parsed.Match(t, now) })
+	}
+
+	var shown []numbered
+	for i, t := range boardTasks(e.svc) {
+		if matchAll(t, filters) {
+			shown = append(shown, numbered{pos: i + 1, t: t})
+		}
+	}
+	switch *format {
+	case formatJSON, formatNDJSON:
+		return writeJSON(e, *format, shown)
+	case formatTable:
+		rows := make([][]string, 0, len(shown))
+		for _, n := range shown {
+			rows = append(rows, taskRow(n))
+		}
+		return writeTable(e, taskHeader, rows)
+	}
+	for _, n := range shown {
+		fmt.Fprintln(e.stdout, listLine(n.pos, n.t))
+	}
+	if len(shown) == 0 {
+		fmt.Fprintln(e.stdout, "no tasks")
+	}
+	return nil
+}
+
+func matchAll(t domain.Task, filters []func(domain.Task) bool) bool {
+	for _, f := range filters {
+		if !f(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// listLine is one row of ls: position, short ID, column, priority, due
+// date, then the title with its star, tags and checklist progress.
+func listLine(n int, t domain.Task) string {
+	prio, due, title := listFields(t)
+	return fmt.Sprintf("%3d  %-7s  %-11s  %-6s  %-10s  %s", n, shortID(t.Id), statusLabel(t.Status), prio, due, title)
+}
+
+// listFields returns the priority, due date and title columns of ls.
+func listFields(t domain.Task) (prio, due, title string) {
+	prio, due = "-", "-"
+	if t.Priority != domain.PriorityNone {
+		prio = task.PriorityLabel(t.Priority)
+	}
+	if t.DueAt != 0 {
+		due = task.FormatDue(t.DueAt)
+	}
+	title = t.Content
+	if t.IsStarred {
+		title = "★ " + title
+	}
+	for _, tag := range t.Tags {
+		title += " #" + tag
+	}
+	if done, total := task.Progress(t); total > 0 {
+		title += fmt.Sprintf(" [%d/%d]", done, total)
+	}
+	return prio, due, title
+}
+
+func runShow(e *env, args []string) error {
+	fs := newFlags(e)
+	format := formatFlag(fs)
+	pos, err := parseFlags(e, fs, args)
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	tasks, err := resolveAll(e, pos)
+	if err != nil {
+		return err
+	}
+	board := boardTasks(e.svc)
+	shown := make([]numbered, 0, len(tasks))
+	for _, t := range tasks {
+		n := numbered{t: t}
+		for i, b := range board {
+			if b.Id == t.Id {
+				n.pos = i + 1
+			}
+		}
+		shown = append(shown, n)
+	}
+	switch *format {
+	case formatJSON, formatNDJSON:
+		return writeJSON(e, *format, shown)
+	case formatTable:
+		rows := make([][]string, 0, len(shown))
+		for _, n := range shown {
+			rows = append(rows, taskRow(n))
+		}
+		return writeTable(e, taskHeader, rows)
+	}
+	for i, n := range shown {
+		if i > 0 {
+			fmt.Fprintln(e.stdout)
+		}
+		writeTask(e, n.t)
+	}
+	return nil
+}
+
+// writeTask prints every field of t, one per line.
+func writeTask(e *env, t domain.Task) {
+	w := e.stdout
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "%-10s %s\n", name+":", value)
+		}
+	}
+	stamp := func(at int64) string {
+		if at == 0 {
+			return ""
+		}
+		return time.Unix(at, 0).Format("2006-01-02 15:04")
+	}
+	fmt.Fprintln(w, t.Content)
+	field("id", t.Id)
+	field("status", statusLabel(t.Status))
+	if t.Priority != domain.PriorityNone {
+		field("priority", task.PriorityLabel(t.Priority))
+	}
+	if t.IsStarred {
+		field("starred", "yes")
+	}
+	field("tags", strings.Join(t.Tags, " "))
+	if t.DueAt != 0 {
+		field("due", task.FormatDue(t.DueAt))
+	}
+	field("created", stamp(t.CreatedAt))
+	field("started", stamp(t.StartedAt))
+	field("updated", stamp(t.UpdatedAt))
+	if done, total := task.Progress(t); total > 0 {
+		field("checklist", fmt.Sprintf("%d/%d", done, total))
+		for _, st := range t.Subtasks {
+			box := "[ ]"
+			if st.Done {
+				box = "[x]"
+			}
+			fmt.Fprintf(w, "  %s %s\n", box, st.Text)
+		}
+	}
+	if len(t.StatusHistory) > 0 {
+		fmt.Fprintln(w, "history:")
+		for _, c := range t.StatusHistory {
+			fmt.Fprintf(w, "  %s  %s\n", stamp(c.At), statusLabel(c.Status))
+		}
+	}
+	if t.Notes != "" {
+		fmt.Fprintln(w, "notes:")
+		for _, line := range strings.Split(t.Notes, "\n") {
+			fmt.Fprintln(w, strings.TrimRight("  "+line, " "))
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+var startCommand = command{
+	name:  "start",
+	usage: "start <n|id>...",
+	run:   func(e *env, args []string) error { return moveTo(e, args, domain.TaskStatusInProgress) },
+}
+
+var doneCommand = command{
+	name:  "done",
+	usage: "done <n|id>...",
+	run:   func(e *env, args []string) error { return moveTo(e, args, domain.TaskStatusDone) },
+}
+
+var mvCommand = command{
+	name:  "mv",
+	usage: "mv <n|id>... <todo|doing|done> | mv --board name <n|id>...",
+	run:   runMove,
+}
+
+var starCommand = command{
+	name:  "star",
+	usage: "star [--off] <n|id>...",
+	run:   runStar,
+}
+
+var rmCommand = command{
+	name:  "rm",
+	usage: "rm <n|id>...",
+	run:   runRemove,
+}
+
+func moveTo(e *env, args []string, status domain.TaskStatus) error {
+	pos, err := parseFlags(e, newFlags(e), args)
+	if err != nil {
+		return err
+	}
+	tasks, err := resolveAll(e, pos)
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		if err := e.svc.Move(t.Id, status); err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "%s: %s\n", statusLabel(status), t.Content)
+	}
+	return nil
+}
+
+func runMove(e *env, args []string) error {
+	fs := newFlags(e)
+	board := fs.String("board", "", "move the tasks to this board instead")
+	pos, err := parseFlags(e, fs, args)
+	if err != nil {
+		return err
+	}
+	if *board != "" {
+		tasks, err := resolveAll(e, pos)
+		if err != nil {
+			return err
+		}
+		for _, t := range tasks {
+			if err := e.svc.MoveToBoard(t.Id, *board); err != nil {
+				return err
+			}
+			fmt.Fprintf(e.stdout, "moved to board %s: %s\n", *board, t.Content)
+		}
+		return nil
+	}
+	if len(pos) < 2 {
+		return usagef("missing task or status")
+	}
+	status, err := parseStatus(pos[len(pos)-1])
+	if err != nil {
+		return err
+	}
+	tasks, err := resolveAll(e, pos[:len(pos)-1])
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		if err := e.svc.Move(t.Id, status); err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "%s: %s\n", statusLabel(status), t.Content)
+	}
+	return nil
+}
+
+// runStar sets or, with --off, clears the star. Unlike s on the board it
+// does not toggle, so running it twice is harmless.
+func runStar(e *env, args []string) error {
+	fs := newFlags(e)
+	off := fs.Bool("off", false, "remove the star instead")
+	pos, err := parseFlags(e, fs, args)
+	if err != nil {
+		return err
+	}
+	tasks, err := resolveAll(e, pos)
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		if t.IsStarred == *off {
+			if err := e.svc.ToggleStar(t.Id); err != nil {
+				return err
+			}
+		}
+		verb := "starred"
+		if *off {
+			verb = "unstarred"
+		}
+		fmt.Fprintf(e.stdout, "%s: %s\n", verb, t.Content)
+	}
+	return nil
+}
+
+func runRemove(e *env, args []string) error {
+	pos, err := parseFlags(e, newFlags(e), args)
+	if err != nil {
+		return err
+	}
+	tasks, err := resolveAll(e, pos)
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		if err := e.svc.Delete(t.Id); err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "trashed: %s\n", t.Content)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+// notFoundError is returned when a task argument does not pick exactly one
+// task; Run exits with ExitNotFound for it.
+type notFoundError struct{ msg string }
+
+func (e notFoundError) Error() string { return e.msg }
+
+// boardStatuses are the columns in the order ls lists them.
+var boardStatuses = []domain.TaskStatus{
+	domain.TaskStatusTodo,
+	domain.TaskStatusInProgress,
+	domain.TaskStatusDone,
+}
+
+// boardTasks lists the tasks on the board in the order ls numbers them:
+// column by column, each sorted as on the board. Positions therefore stay
+// the same whatever ls filters out.
+func boardTasks(svc *task.Service) []domain.Task {
+	mode, _ := svc.GetSortMode()
+	m := svc.Tasks()
+	var out []domain.Task
+	for _, st := range boardStatuses {
+		list := m[st]
+		for _, i := range task.SortedOrder(list, mode) {
+			out = append(out, list[i])
+		}
+	}
+	return out
+}
+
+// shortIDLen is how much of a task ID ls prints. IDs are creation times in
+// nanoseconds, so it is their ends that tell tasks apart.
+const shortIDLen = 7
+
+func shortID(id string) string {
+	if len(id) <= shortIDLen {
+		return id
+	}
+	return id[len(id)-shortIDLen:]
+}
+
+// resolveIn finds a task by 1-based position in list or by ID. Besides the
+// full ID, a suffix such as the short ID shown by ls, or a prefix, will do
+// as long as it matches a single task.
+func resolveIn(list []domain.Task, ref string) (domain.Task, error) {
+	if n, err := strconv.Atoi(ref); err == nil && n >= 1 && n <= len(list) && len(ref) < 6 {
+		return list[n-1], nil
+	}
+	for _, match := range []func(string, string) bool{
+		func(id, ref string) bool { return id == ref },
+		strings.HasSuffix,
+		strings.HasPrefix,
+	} {
+		var found []domain.Task
+		for _, t := range list {
+			if match(t.Id, ref) {
+				found = append(found, t)
+			}
+		}
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return found[0], nil
+		default:
+			return domain.Task{}, notFoundError{msg: fmt.Sprintf("%q matches %d tasks", ref, len(found))}
+		}
+	}
+	return domain.Task{}, notFoundError{msg: fmt.Sprintf("no task matches %q", ref)}
+}
+
+// resolveAll resolves every argument against the board before anything is
+// changed, so a typo in the third argument does not leave the first two
+// half done.
+func resolveAll(e *env, refs []string) ([]domain.Task, error) {
+	if len(refs) == 0 {
+		return nil, usagef("missing task")
+	}
+	list := boardTasks(e.svc)
+	out := make([]domain.Task, 0, len(refs))
+	for _, ref := range refs {
+		t, err := resolveIn(list, ref)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// parseStatus reads a column name as accepted on the command line.
+func parseStatus(name string) (domain.TaskStatus, error) {
+	switch strings.ToLower(name) {
+	case "todo":
+		return domain.TaskStatusTodo, nil
+	case "doing", "progress", "in-progress", "in_progress":
+		return domain.TaskStatusInProgress, nil
+	case "done":
+		return domain.TaskStatusDone, nil
+	}
+	return 0, usagef("unknown status %q: use todo, doing or done", name)
+}
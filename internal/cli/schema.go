@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+// schemaVersion is the version of the json and ndjson output. Fields may be
+// added within a version; renaming or removing one needs a new version.
+const schemaVersion = 1
+
+// taskList is the json output of the listing commands.
+type taskList struct {
+	Version int          `json:"version"`
+	Tasks   []taskOutput `json:"tasks"`
+}
+
+// ndjsonTask is a line of ndjson output. With no envelope around the
+// stream, each line carries the schema version itself.
+type ndjsonTask struct {
+	Version int `json:"version"`
+	taskOutput
+}
+
+// taskOutput is a task in json and ndjson output, one object per line in
+// the latter. It is kept apart from domain.Task and from the tasks file so
+// that neither can change it by accident. Every field is always present:
+// absent values are null, empty lists are [].
+type taskOutput struct {
+	// Position is the task's number in ls, or in trash ls for trashed tasks
+	Position int    `json:"position"`
+	ID       string `json:"id"`
+	ShortID  string `json:"short_id"`
+	Title    string `json:"title"`
+	// Status is todo, in_progress, done or trashed
+	Status string `json:"status"`
+	// Priority is none, low, medium, high or urgent
+	Priority string   `json:"priority"`
+	Starred  bool     `json:"starred"`
+	Tags     []string `json:"tags"`
+	// Due is the due day as YYYY-MM-DD
+	Due       *string         `json:"due"`
+	Notes     string          `json:"notes"`
+	Checklist []checklistItem `json:"checklist"`
+	// Times are RFC 3339 with the local offset
+	CreatedAt *string        `json:"created_at"`
+	StartedAt *string        `json:"started_at"`
+	UpdatedAt *string        `json:"updated_at"`
+	History   []statusChange `json:"history"`
+	// DeletedAt and TrashedFrom are set for trashed tasks only
+	DeletedAt   *string `json:"deleted_at"`
+	TrashedFrom *string `json:"trashed_from"`
+}
+
+type checklistItem struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+type statusChange struct {
+	Status string `json:"status"`
+	At     string `json:"at"`
+}
+
+var outputStatusNames = map[domain.TaskStatus]string{
+	domain.TaskStatusTodo:       "todo",
+	domain.TaskStatusInProgress: "in_progress",
+	domain.TaskStatusDone:       "done",
+	domain.TaskStatusTrashed:    "trashed",
+}
+
+func newTaskOutput(pos int, t domain.Task) taskOutput {
+	out := taskOutput{
+		Position:  pos,
+		ID:        t.Id,
+		ShortID:   shortID(t.Id),
+		Title:     t.Content,
+		Status:    outputStatusNames[t.Status],
+		Priority:  task.PriorityLabel(t.Priority),
+		Starred:   t.IsStarred,
+		Tags:      append([]string{}, t.Tags...),
+		Notes:     t.Notes,
+		Checklist: []checklistItem{},
+		CreatedAt: outputTime(t.CreatedAt),
+		StartedAt: outputTime(t.StartedAt),
+		UpdatedAt: outputTime(t.UpdatedAt),
+		History:   []statusChange{},
+	}
+	if t.DueAt != 0 {
+		due := task.FormatDue(t.DueAt)
+		out.Due = &due
+	}
+	for _, st := range t.Subtasks {
+		out.Checklist = append(out.Checklist, checklistItem{Text: st.Text, Done: st.Done})
+	}
+	for _, c := range t.StatusHistory {
+		out.History = append(out.History, statusChange{Status: outputStatusNames[c.Status], At: time.Unix(c.At, 0).Format(time.RFC3339)})
+	}
+	if t.Status == domain.TaskStatusTrashed {
+		out.DeletedAt = outputTime(t.DeletedAt)
+		from := outputStatusNames[t.TrashedFrom]
+		out.TrashedFrom = &from
+	}
+	return out
+}
+
+// outputTime formats a Unix time, or returns nil for 0.
+func outputTime(at int64) *string {
+	if at == 0 {
+		return nil
+	}
+	s := time.Unix(at, 0).Format(time.RFC3339)
+	return &s
+}
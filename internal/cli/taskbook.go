@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/codec"
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+var importTaskbookCommand = command{
+	name:  "import-taskbook",
+	usage: "import-taskbook [--dry-run] [--no-archive] [dir]",
+	run:   runImportTaskbook,
+}
+
+// runImportTaskbook imports the items of a Taskbook directory. Items that
+// are already on the board, with the same text and creation time, are
+// skipped, so running it again only brings in what is new.
+func runImportTaskbook(e *env, args []string) error {
+	flags := newFlags(e)
+	dryRun := flags.Bool("dry-run", false, "show what would change without changing anything")
+	noArchive := flags.Bool("no-archive", false, "leave out archived items instead of putting them in the trash")
+	pos, err := parseFlags(e, flags, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 1 {
+		return usagef("import-taskbook takes one directory")
+	}
+	dir := ""
+	if len(pos) == 1 {
+		dir = pos[0]
+	} else if dir, err = taskbookDir(); err != nil {
+		return err
+	}
+
+	storage, err := os.Open(filepath.Join(dir, "storage", "storage.json"))
+	if err != nil {
+		return err
+	}
+	defer storage.Close()
+	var archive io.Reader
+	if !*noArchive {
+		f, err := os.Open(filepath.Join(dir, "archive", "archive.json"))
+		switch {
+		case err == nil:
+			defer f.Close()
+			archive = f
+		case !errors.Is(err, fs.ErrNotExist):
+			return err
+		}
+	}
+	tasks, skips, err := codec.DecodeTaskbook(storage, archive, time.Now())
+	if err != nil {
+		return err
+	}
+
+	type key struct {
+		content string
+		created int64
+	}
+	onBoard := map[key]bool{}
+	for _, list := range e.svc.Tasks() {
+		for _, t := range list {
+			onBoard[key{t.Content, t.CreatedAt}] = true
+		}
+	}
+	fresh := tasks[:0:0]
+	for _, t := range tasks {
+		if onBoard[key{t.Content, t.CreatedAt}] {
+			skips = append(skips, codec.TaskbookSkip{Description: t.Content, Reason: "already on the board"})
+			continue
+		}
+		fresh = append(fresh, t)
+	}
+
+	res, err := e.svc.Import(fresh, task.ImportOptions{DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+	for _, s := range skips {
+		writeTaskbookSkip(e, s)
+	}
+	writeImportResult(e, res, *dryRun)
+	if n := countTrashed(res); n > 0 && *dryRun {
+		fmt.Fprintf(e.stdout, "archived items that would go to the trash: %d\n", n)
+	} else if n > 0 {
+		fmt.Fprintf(e.stdout, "archived items put in the trash: %d\n", n)
+	}
+	fmt.Fprintf(e.stdout, "skipped %d\n", len(skips))
+	return nil
+}
+
+func writeTaskbookSkip(e *env, s codec.TaskbookSkip) {
+	var what []string
+	if s.Ref != "" {
+		what = append(what, s.Ref)
+	}
+	if s.Description != "" {
+		what = append(what, s.Description)
+	}
+	fmt.Fprintf(e.stdout, "skipped %s: %s\n", strings.Join(what, " "), s.Reason)
+}
+
+func countTrashed(res task.ImportResult) int {
+	n := 0
+	for i, t := range res.Tasks {
+		if res.Actions[i] == task.ImportAdded && t.Status == domain.TaskStatusTrashed {
+			n++
+		}
+	}
+	return n
+}
+
+// taskbookDir returns the .taskbook directory Taskbook uses: the one inside
+// taskbookDirectory when ~/.taskbook.json sets it, else the one in the home
+// directory.
+func taskbookDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("find home directory: %w", err)
+	}
+	root := home
+	data, err := os.ReadFile(filepath.Join(home, ".taskbook.json"))
+	if err == nil {
+		var cfg struct {
+			TaskbookDirectory string `json:"taskbookDirectory"`
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return "", fmt.Errorf("read ~/.taskbook.json: %w", err)
+		}
+		if d := cfg.TaskbookDirectory; d != "" {
+			if d == "~" || strings.HasPrefix(d, "~/") {
+				d = filepath.Join(home, d[1:])
+			}
+			root = d
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	return filepath.Join(root, ".taskbook"), nil
+}
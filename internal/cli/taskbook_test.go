@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/testutil"
+)
+
+var taskbookFixture = filepath.Join("..", "codec", "testdata", "taskbook")
+
+func TestImportTaskbook(t *testing.T) {
+	tests := []struct {
+		name string
+		// runs are the argument lists of consecutive import-taskbook runs
+		runs [][]string
+		// want holds lines each run must print
+		want      [][]string
+		onBoard   int
+		inTrash   int
+		notOutput []string
+	}{
+		{
+			name: "dry run changes nothing",
+			runs: [][]string{{"--dry-run"}},
+			want: [][]string{{
+				"skipped #5: not a Taskbook item",
+				"skipped #4: no description",
+				`skipped #2 Read docs: board "@bad!board" not kept: not a valid tag`,
+				"would add: Fix the build",
+				"would add 6, would update 0, unchanged 0",
+				"archived items that would go to the trash: 2",
+				"skipped 3",
+			}},
+		},
+		{
+			name: "import, then again",
+			runs: [][]string{nil, nil},
+			want: [][]string{
+				{"added: Buy milk", "added 6, updated 0, unchanged 0", "archived items put in the trash: 2", "skipped 3"},
+				{"skipped Buy milk: already on the board", "skipped Old task: already on the board",
+					"added 0, updated 0, unchanged 0", "skipped 9"},
+			},
+			onBoard: 4,
+			inTrash: 2,
+		},
+		{
+			name:      "without the archive",
+			runs:      [][]string{{"--no-archive"}},
+			want:      [][]string{{"added 4, updated 0, unchanged 0", "skipped 3"}},
+			onBoard:   4,
+			notOutput: []string{"Old task", "trash"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := testutil.TempService(t)
+			for i, args := range tt.runs {
+				out := run(t, svc, append(append([]string{"import-taskbook"}, args...), taskbookFixture)...)
+				lines := strings.Split(out, "\n")
+				for _, w := range tt.want[i] {
+					if !slices.Contains(lines, w) {
+						t.Fatalf("run %d: output lacks %q:\n%s", i+1, w, out)
+					}
+				}
+				for _, w := range tt.notOutput {
+					if strings.Contains(out, w) {
+						t.Fatalf("run %d: output mentions %q:\n%s", i+1, w, out)
+					}
+				}
+			}
+			tasks := svc.Tasks()
+			onBoard := len(tasks[domain.TaskStatusTodo]) + len(tasks[domain.TaskStatusInProgress]) + len(tasks[domain.TaskStatusDone])
+			if onBoard != tt.onBoard || len(tasks[domain.TaskStatusTrashed]) != tt.inTrash {
+				t.Fatalf("%d tasks on the board and %d in the trash, want %d and %d",
+					onBoard, len(tasks[domain.TaskStatusTrashed]), tt.onBoard, tt.inTrash)
+			}
+		})
+	}
+}
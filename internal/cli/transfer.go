@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/codec"
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+var exportCommand = command{
+	name:  "export",
+	usage: "export [--format markdown|todotxt] [--output file] [--no-ids]",
+	run:   runExport,
+}
+
+var importCommand = command{
+	name:  "import",
+	usage: "import [--format markdown|todotxt] [--dry-run] [file]",
+	run:   runImport,
+}
+
+// Formats of export and import.
+const (
+	formatMarkdown = "markdown"
+	formatTodoTxt  = "todotxt"
+)
+
+// transferFormat returns the canonical name of format f.
+func transferFormat(f string) (string, error) {
+	switch f {
+	case formatMarkdown, "md":
+		return formatMarkdown, nil
+	case formatTodoTxt, "todo.txt":
+		return formatTodoTxt, nil
+	}
+	return "", usagef("unknown format %q: use markdown or todotxt", f)
+}
+
+func encodeBoard(w io.Writer, format string, b codec.Board, ids bool) error {
+	if format == formatTodoTxt {
+		return codec.EncodeTodoTxt(w, b, ids)
+	}
+	return codec.EncodeMarkdown(w, b, ids)
+}
+
+// decodeTasks reads tasks in format, along with the import options that
+// keep the fields the format cannot hold.
+func decodeTasks(r io.Reader, format string) ([]domain.Task, task.ImportOptions, error) {
+	if format == formatTodoTxt {
+		tasks, err := codec.DecodeTodoTxt(r, time.Now())
+		return tasks, task.ImportOptions{NoNotes: true, NoChecklist: true}, err
+	}
+	tasks, err := codec.DecodeMarkdown(r, time.Now())
+	return tasks, task.ImportOptions{}, err
+}
+
+func runExport(e *env, args []string) error {
+	fs := newFlags(e)
+	format := fs.String("format", formatMarkdown, "file format: markdown or todotxt")
+	output := fs.String("output", "", "write to this file instead of standard output")
+	noIDs := fs.Bool("no-ids", false, "leave out the task IDs, which import uses to update tasks")
+	pos, err := parseFlags(e, fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usagef("unexpected argument %q", pos[0])
+	}
+	f, err := transferFormat(*format)
+	if err != nil {
+		return err
+	}
+
+	mode, _ := e.svc.GetSortMode()
+	all := e.svc.Tasks()
+	board := codec.Board{Name: e.svc.BoardName(), Tasks: map[domain.TaskStatus][]domain.Task{}}
+	for _, st := range codec.Columns {
+		for _, i := range task.SortedOrder(all[st], mode) {
+			board.Tasks[st] = append(board.Tasks[st], all[st][i])
+		}
+	}
+
+	if *output == "" {
+		return encodeBoard(e.stdout, f, board, !*noIDs)
+	}
+	out, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	err = encodeBoard(out, f, board, !*noIDs)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("write %s: %w", *output, err)
+	}
+	return nil
+}
+
+func runImport(e *env, args []string) error {
+	fs := newFlags(e)
+	format := fs.String("format", formatMarkdown, "file format: markdown or todotxt")
+	dryRun := fs.Bool("dry-run", false, "show what would change without changing anything")
+	pos, err := parseFlags(e, fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 1 {
+		return usagef("import takes one file")
+	}
+	f, err := transferFormat(*format)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = e.stdin
+	if len(pos) == 1 && pos[0] != "-" {
+		in, err := os.Open(pos[0])
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		r = in
+	}
+	tasks, opts, err := decodeTasks(r, f)
+	if err != nil {
+		return err
+	}
+	opts.DryRun = *dryRun
+	res, err := e.svc.Import(tasks, opts)
+	if err != nil {
+		return err
+	}
+	writeImportResult(e, res, *dryRun)
+	return nil
+}
+
+// writeImportResult lists added and updated tasks, then the totals.
+func writeImportResult(e *env, res task.ImportResult, dryRun bool) {
+	verbs := map[task.ImportAction]string{task.ImportAdded: "added", task.ImportUpdated: "updated"}
+	if dryRun {
+		verbs = map[task.ImportAction]string{task.ImportAdded: "would add", task.ImportUpdated: "would update"}
+	}
+	for i, t := range res.Tasks {
+		if verb, ok := verbs[res.Actions[i]]; ok {
+			fmt.Fprintf(e.stdout, "%s: %s\n", verb, t.Content)
+		}
+	}
+	fmt.Fprintf(e.stdout, "%s %d, %s %d, unchanged %d\n", verbs[task.ImportAdded], res.Count(task.ImportAdded),
+		verbs[task.ImportUpdated], res.Count(task.ImportUpdated), res.Count(task.ImportUnchanged))
+}
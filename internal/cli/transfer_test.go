@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+	"github.com/hungtrd/lazytodo/internal/testutil"
+)
+
+// fillBoard adds tasks that use every field the export formats know of.
+func fillBoard(t *testing.T, svc *task.Service) {
+	t.Helper()
+	ship, err := svc.Add("Ship release #ops #release due:2026-10-23")
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := []error{
+		svc.ToggleStar(ship.Id),
+		svc.SetNotes(ship.Id, "Remember the changelog.\n\n    make release"),
+	}
+	_, err = svc.ShiftPriority(ship.Id, 3)
+	steps = append(steps, err)
+	_, err = svc.AddSubtask(ship.Id, "tag the build")
+	steps = append(steps, err)
+	_, err = svc.AddSubtask(ship.Id, "announce")
+	steps = append(steps, err)
+	_, err = svc.ToggleSubtask(ship.Id, 0)
+	steps = append(steps, err)
+
+	doing, err := svc.Add("Write report #work")
+	steps = append(steps, err, svc.Move(doing.Id, domain.TaskStatusInProgress))
+	done, err := svc.Add("File taxes")
+	steps = append(steps, err, svc.Move(done.Id, domain.TaskStatusDone))
+	for _, err := range steps {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	for _, format := range []string{formatMarkdown, formatTodoTxt} {
+		t.Run(format, func(t *testing.T) {
+			svc := testutil.TempService(t)
+			fillBoard(t, svc)
+			before := svc.Tasks()
+
+			exported := run(t, svc, "export", "--format", format)
+			var stdout, stderr strings.Builder
+			code := Run(svc, []string{"import", "--format", format}, strings.NewReader(exported), &stdout, &stderr)
+			if code != ExitOK {
+				t.Fatalf("import: exit %d: %s", code, stderr.String())
+			}
+			if want := "added 0, updated 0, unchanged 3\n"; stdout.String() != want {
+				t.Fatalf("import printed %q, want %q", stdout.String(), want)
+			}
+			if after := svc.Tasks(); !reflect.DeepEqual(before, after) {
+				t.Fatalf("board changed:\nbefore %+v\nafter  %+v", before, after)
+			}
+		})
+	}
+}
+
+// TestTodoTxtImportKeepsNotes checks that updating a task from todo.txt,
+// which has no room for notes or checklists, leaves them alone.
+func TestTodoTxtImportKeepsNotes(t *testing.T) {
+	svc := testutil.TempService(t)
+	fillBoard(t, svc)
+	exported := run(t, svc, "export", "--format", formatTodoTxt)
+	edited := strings.Replace(exported, "Ship release", "Ship the release", 1)
+
+	var stdout, stderr strings.Builder
+	if code := Run(svc, []string{"import", "--format", formatTodoTxt}, strings.NewReader(edited), &stdout, &stderr); code != ExitOK {
+		t.Fatalf("import: exit %d: %s", code, stderr.String())
+	}
+	if !strings.HasSuffix(stdout.String(), "added 0, updated 1, unchanged 2\n") {
+		t.Fatalf("import printed %q", stdout.String())
+	}
+	ship := svc.Tasks()[domain.TaskStatusTodo][0]
+	if ship.Content != "Ship the release" {
+		t.Fatalf("title %q, want the edited one", ship.Content)
+	}
+	if ship.Notes == "" || len(ship.Subtasks) != 2 {
+		t.Fatalf("notes %q and checklist %v were not kept", ship.Notes, ship.Subtasks)
+	}
+}
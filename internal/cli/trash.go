@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+var trashCommand = command{
+	name:  "trash",
+	usage: "trash [ls [--format f] | restore <n|id>... | purge <n|id>... | empty]",
+	run:   runTrash,
+}
+
+func runTrash(e *env, args []string) error {
+	sub := "ls"
+	// flags without a subcommand belong to ls, as in "trash --format json"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		sub, args = args[0], args[1:]
+	}
+	switch sub {
+	case "ls", "list":
+		return trashList(e, args)
+	case "restore":
+		return trashEach(e, args, func(t domain.Task) error {
+			restored, err := e.svc.Restore(t.Id)
+			if err == nil {
+				fmt.Fprintf(e.stdout, "restored to %s: %s\n", statusLabel(restored.Status), restored.Content)
+			}
+			return err
+		})
+	case "purge":
+		return trashEach(e, args, func(t domain.Task) error {
+			err := e.svc.Purge(t.Id)
+			if err == nil {
+				fmt.Fprintf(e.stdout, "purged: %s\n", t.Content)
+			}
+			return err
+		})
+	case "empty":
+		if len(args) > 0 {
+			return usagef("empty takes no arguments")
+		}
+		n, err := e.svc.EmptyTrash()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "purged %d tasks\n", n)
+		return nil
+	default:
+		return usagef("unknown trash command %q", sub)
+	}
+}
+
+func trashList(e *env, args []string) error {
+	fs := newFlags(e)
+	format := formatFlag(fs)
+	pos, err := parseFlags(e, fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usagef("ls takes no arguments")
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	trash := e.svc.Trash()
+	switch *format {
+	case formatJSON, formatNDJSON:
+		list := make([]numbered, 0, len(trash))
+		for i, t := range trash {
+			list = append(list, numbered{pos: i + 1, t: t})
+		}
+		return writeJSON(e, *format, list)
+	case formatTable:
+		rows := make([][]string, 0, len(trash))
+		for i, t := range trash {
+			rows = append(rows, []string{fmt.Sprint(i + 1), shortID(t.Id), time.Unix(t.DeletedAt, 0).Format("2006-01-02 15:04"), statusLabel(t.TrashedFrom), t.Content})
+		}
+		return writeTable(e, []string{"#", "ID", "DELETED", "FROM", "TITLE"}, rows)
+	}
+	if len(trash) == 0 {
+		fmt.Fprintln(e.stdout, "trash is empty")
+		return nil
+	}
+	for i, t := range trash {
+		fmt.Fprintf(e.stdout, "%3d  %s  %-11s  %s  (%s)\n", i+1,
+			time.Unix(t.DeletedAt, 0).Format("2006-01-02 15:04"), statusLabel(t.TrashedFrom), t.Content, t.Id)
+	}
+	return nil
+}
+
+// trashEach resolves every argument against the trash before acting, so a
+// typo in the third argument does not leave the first two half done.
+func trashEach(e *env, args []string, fn func(domain.Task) error) error {
+	if len(args) == 0 {
+		return usagef("missing task")
+	}
+	trash := e.svc.Trash()
+	targets := make([]domain.Task, 0, len(args))
+	for _, a := range args {
+		t, err := resolveIn(trash, a)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, t)
+	}
+	for _, t := range targets {
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func statusLabel(s domain.TaskStatus) string {
+	switch s {
+	case domain.TaskStatusTodo:
+		return "todo"
+	case domain.TaskStatusInProgress:
+		return "in-progress"
+	case domain.TaskStatusDone:
+		return "done"
+	case domain.TaskStatusTrashed:
+		return "trashed"
+	default:
+		return "unknown"
+	}
+}
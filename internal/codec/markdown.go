@@ -0,0 +1,213 @@
+// Package codec reads and writes boards in formats used outside lazytodo.
+package codec
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+// Board is what the encoders write: a name and the tasks of each column,
+// each list already in board order.
+type Board struct {
+	Name  string
+	Tasks map[domain.TaskStatus][]domain.Task
+}
+
+// Columns are written in this order.
+var Columns = []domain.TaskStatus{
+	domain.TaskStatusTodo,
+	domain.TaskStatusInProgress,
+	domain.TaskStatusDone,
+}
+
+var columnHeadings = map[domain.TaskStatus]string{
+	domain.TaskStatusTodo:       "Todo",
+	domain.TaskStatusInProgress: "In Progress",
+	domain.TaskStatusDone:       "Done",
+}
+
+const (
+	starMark      = "★"
+	priorityToken = "priority:"
+)
+
+var (
+	mdTaskPattern  = regexp.MustCompile(`^(\s*)[-*+] \[([ xX])\] ?(.*)$`)
+	mdIDPattern    = regexp.MustCompile(`\s*<!--\s*id:(\S+)\s*-->`)
+	mdQuotePattern = regexp.MustCompile(`^\s+> ?(.*)$`)
+	mdHeadPattern  = regexp.MustCompile(`^#{1,6}\s`)
+)
+
+// EncodeMarkdown writes b as a markdown checklist: a heading per column and
+// a "- [ ]" item per task, "- [x]" in Done. Stars, tags, due dates and
+// priorities are written inline the way tasks are typed on the board,
+// checklists as nested items and notes as an indented quote. With ids
+// set, each task ends in an HTML comment holding its ID, which is
+// invisible once rendered and lets DecodeMarkdown match it up again.
+func EncodeMarkdown(w io.Writer, b Board, ids bool) error {
+	bw := bufio.NewWriter(w)
+	if b.Name != "" {
+		fmt.Fprintf(bw, "# %s\n\n", b.Name)
+	}
+	for i, st := range Columns {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		fmt.Fprintf(bw, "## %s\n\n", columnHeadings[st])
+		for _, t := range b.Tasks[st] {
+			writeMarkdownTask(bw, t, ids)
+		}
+	}
+	return bw.Flush()
+}
+
+func writeMarkdownTask(w *bufio.Writer, t domain.Task, ids bool) {
+	box := "[ ]"
+	if t.Status == domain.TaskStatusDone {
+		box = "[x]"
+	}
+	line := "- " + box + " "
+	if t.IsStarred {
+		line += starMark + " "
+	}
+	line += t.Content
+	for _, tag := range t.Tags {
+		line += " #" + tag
+	}
+	if t.DueAt != 0 {
+		line += " due:" + task.FormatDue(t.DueAt)
+	}
+	if t.Priority != domain.PriorityNone {
+		line += " " + priorityToken + task.PriorityLabel(t.Priority)
+	}
+	if ids {
+		line += " <!-- id:" + t.Id + " -->"
+	}
+	w.WriteString(line + "\n")
+	for _, st := range t.Subtasks {
+		box := "[ ]"
+		if st.Done {
+			box = "[x]"
+		}
+		fmt.Fprintf(w, "  - %s %s\n", box, st.Text)
+	}
+	if t.Notes != "" {
+		for _, l := range strings.Split(t.Notes, "\n") {
+			w.WriteString(strings.TrimRight("  > "+l, " ") + "\n")
+		}
+	}
+}
+
+// DecodeMarkdown reads tasks written by EncodeMarkdown, possibly edited by
+// hand. A ticked item is done; an open one belongs to the column heading it
+// is under, or to Todo when that heading is not a column. Lines that are
+// neither items nor a task's notes are ignored, so the checklist can sit
+// inside a longer document. Tasks without an ID comment get an empty Id.
+func DecodeMarkdown(r io.Reader, now time.Time) ([]domain.Task, error) {
+	var out []domain.Task
+	column := domain.TaskStatusTodo
+	current := -1
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimRight(sc.Text(), " \t\r")
+		if mdHeadPattern.MatchString(line) {
+			column = headingColumn(line)
+			current = -1
+			continue
+		}
+		if m := mdTaskPattern.FindStringSubmatch(line); m != nil {
+			done := m[2] != " "
+			if len(m[1]) >= 2 && current >= 0 {
+				t := &out[current]
+				t.Subtasks = append(t.Subtasks, domain.Subtask{Text: strings.TrimSpace(m[3]), Done: done})
+				continue
+			}
+			t, err := parseMarkdownTask(m[3], now)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			t.Status = column
+			if done {
+				t.Status = domain.TaskStatusDone
+			} else if column == domain.TaskStatusDone {
+				t.Status = domain.TaskStatusTodo
+			}
+			out = append(out, t)
+			current = len(out) - 1
+			continue
+		}
+		if m := mdQuotePattern.FindStringSubmatch(line); m != nil && current >= 0 {
+			t := &out[current]
+			if t.Notes != "" || m[1] != "" {
+				t.Notes += m[1] + "\n"
+			}
+			continue
+		}
+		if line != "" && !strings.HasPrefix(line, " ") {
+			// text between tasks ends the one before
+			current = -1
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read markdown: %w", err)
+	}
+	for i := range out {
+		out[i].Notes = strings.TrimRight(out[i].Notes, "\n")
+	}
+	return out, nil
+}
+
+// headingColumn returns the column a heading names, or Todo for any other
+// heading.
+func headingColumn(line string) domain.TaskStatus {
+	name := strings.ToLower(strings.TrimSpace(strings.TrimLeft(line, "#")))
+	switch name {
+	case "in progress", "doing", "in-progress":
+		return domain.TaskStatusInProgress
+	case "done":
+		return domain.TaskStatusDone
+	}
+	return domain.TaskStatusTodo
+}
+
+// parseMarkdownTask reads the text of a task item: an optional star, the
+// title with its #tags and due date, an optional priority and ID comment.
+func parseMarkdownTask(text string, now time.Time) (domain.Task, error) {
+	var t domain.Task
+	if m := mdIDPattern.FindStringSubmatch(text); m != nil {
+		t.Id = m[1]
+		text = mdIDPattern.ReplaceAllString(text, "")
+	}
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, starMark) {
+		t.IsStarred = true
+		text = strings.TrimSpace(strings.TrimPrefix(text, starMark))
+	}
+	words := strings.Fields(text)
+	kept := words[:0]
+	for _, w := range words {
+		if len(w) > len(priorityToken) && strings.EqualFold(w[:len(priorityToken)], priorityToken) {
+			p, err := task.ParsePriority(w[len(priorityToken):])
+			if err != nil {
+				return domain.Task{}, err
+			}
+			t.Priority = p
+			continue
+		}
+		kept = append(kept, w)
+	}
+	e, err := task.ParseText(strings.Join(kept, " "), now)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	t.Content, t.Tags, t.DueAt = e.Content, e.Tags, e.DueAt
+	return t, nil
+}
@@ -0,0 +1,128 @@
+package codec
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+func TestDecodeMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []domain.Task
+	}{
+		{
+			name:  "columns from headings and boxes",
+			input: "## Todo\n\n- [ ] one\n\n## In Progress\n\n- [ ] two\n- [x] three\n\n## Done\n\n- [ ] reopened\n",
+			want: []domain.Task{
+				{Content: "one", Status: domain.TaskStatusTodo},
+				{Content: "two", Status: domain.TaskStatusInProgress},
+				{Content: "three", Status: domain.TaskStatusDone},
+				{Content: "reopened", Status: domain.TaskStatusTodo},
+			},
+		},
+		{
+			name:  "other headings and prose",
+			input: "# Release\n\nSome text.\n\n### Doing\n* [ ] a\n\n### Notes\n+ [X] b\n",
+			want: []domain.Task{
+				{Content: "a", Status: domain.TaskStatusInProgress},
+				{Content: "b", Status: domain.TaskStatusDone},
+			},
+		},
+		{
+			name: "inline attributes, checklist and notes",
+			input: "- [ ] ★ Ship release #ops due:2026-10-23 priority:high <!-- id:42 -->\n" +
+				"  - [x] tag the build\n  - [ ] announce\n  > first\n  >\n  >     code\n",
+			want: []domain.Task{{
+				Id: "42", Content: "Ship release", Status: domain.TaskStatusTodo, IsStarred: true,
+				Priority: domain.PriorityHigh, Tags: []string{"ops"}, DueAt: day(2026, 10, 23),
+				Subtasks: []domain.Subtask{{Text: "tag the build", Done: true}, {Text: "announce"}},
+				Notes:    "first\n\n    code",
+			}},
+		},
+		{
+			name:  "text between tasks ends the one before",
+			input: "- [ ] a\nparagraph\n  > not a note\n",
+			want:  []domain.Task{{Content: "a", Status: domain.TaskStatusTodo}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeMarkdown(strings.NewReader(tt.input), testNow)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeMarkdownErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"## Todo\n\n- [ ] a priority:huge\n", "line 3:"},
+		{"- [ ] a due:whenever\n", "line 1:"},
+		{"- [ ] #onlytag\n", "line 1: content is empty"},
+	}
+	for _, tt := range tests {
+		_, err := DecodeMarkdown(strings.NewReader(tt.input), testNow)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: error %v, want it to contain %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	tasks := []domain.Task{
+		{Id: "1", Content: "Ship release", Status: domain.TaskStatusTodo, IsStarred: true,
+			Priority: domain.PriorityUrgent, Tags: []string{"ops", "release"}, DueAt: day(2026, 10, 23),
+			Subtasks: []domain.Subtask{{Text: "tag the build", Done: true}, {Text: "announce"}},
+			Notes:    "# Plan\n\n- changelog\n\n    make release"},
+		{Id: "2", Content: "Plain", Status: domain.TaskStatusTodo},
+		{Id: "3", Content: "Write report", Status: domain.TaskStatusInProgress, Priority: domain.PriorityLow},
+		{Id: "4", Content: "File taxes", Status: domain.TaskStatusDone, Notes: "done early"},
+	}
+	board := Board{Name: "work", Tasks: map[domain.TaskStatus][]domain.Task{}}
+	for _, task := range tasks {
+		board.Tasks[task.Status] = append(board.Tasks[task.Status], task)
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeMarkdown(&buf, board, true); err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeMarkdown(&buf, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, tasks) {
+		t.Fatalf("round trip changed the tasks:\ngot  %+v\nwant %+v", got, tasks)
+	}
+}
+
+func TestEncodeMarkdown(t *testing.T) {
+	board := Board{Name: "work", Tasks: map[domain.TaskStatus][]domain.Task{
+		domain.TaskStatusTodo: {{Id: "1", Content: "Ship", IsStarred: true, Tags: []string{"ops"},
+			Priority: domain.PriorityHigh, Subtasks: []domain.Subtask{{Text: "tag", Done: true}}, Notes: "a\n\nb"}},
+		domain.TaskStatusDone: {{Id: "2", Content: "Docs", Status: domain.TaskStatusDone}},
+	}}
+	var buf bytes.Buffer
+	if err := EncodeMarkdown(&buf, board, false); err != nil {
+		t.Fatal(err)
+	}
+	want := "# work\n\n" +
+		"## Todo\n\n- [ ] ★ Ship #ops priority:high\n  - [x] tag\n  > a\n  >\n  > b\n\n" +
+		"## In Progress\n\n\n" +
+		"## Done\n\n- [x] Docs\n"
+	if buf.String() != want {
+		t.Fatalf("got %q\nwant %q", buf.String(), want)
+	}
+}
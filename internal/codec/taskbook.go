@@ -0,0 +1,147 @@
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+// taskbookDefaultBoard is the board Taskbook puts items on when none is
+// given. It maps to no tag.
+const taskbookDefaultBoard = "My Board"
+
+// TaskbookNoteTag marks tasks made from Taskbook notes, which have no
+// lazytodo equivalent.
+const TaskbookNoteTag = "note"
+
+// taskbookItem is an entry of Taskbook's storage.json or archive.json.
+// Notes have no isComplete, inProgress or priority.
+type taskbookItem struct {
+	Timestamp   int64    `json:"_timestamp"`
+	Description string   `json:"description"`
+	IsStarred   bool     `json:"isStarred"`
+	Boards      []string `json:"boards"`
+	IsTask      bool     `json:"_isTask"`
+	IsComplete  bool     `json:"isComplete"`
+	InProgress  bool     `json:"inProgress"`
+	Priority    int      `json:"priority"`
+}
+
+// TaskbookSkip is something DecodeTaskbook left out: a whole item, or one
+// of its boards when Reason says so.
+type TaskbookSkip struct {
+	// Ref names the item as Taskbook does, e.g. "#12" or "archive #12".
+	Ref         string
+	Description string
+	Reason      string
+}
+
+// DecodeTaskbook converts the items of Taskbook's storage.json and, if
+// archive is not nil, archive.json. Tasks keep their star and creation
+// time; a complete task goes to Done and one in progress to In Progress.
+// Priority 2 is medium and 3 high. Boards other than "My Board" become
+// tags, without Taskbook's "@". Notes become Todo tasks tagged note.
+// Archived items, which Taskbook keeps after deleting them, go to the
+// trash with the column they would have been in, deleted at now. Items are
+// returned newest first, so that an import keeps them in Taskbook's order
+// at the top of each column; items that cannot be read are reported as
+// skipped.
+func DecodeTaskbook(storage, archive io.Reader, now time.Time) ([]domain.Task, []TaskbookSkip, error) {
+	tasks, skips, err := decodeTaskbookFile(storage, "#", false, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read storage: %w", err)
+	}
+	if archive != nil {
+		archived, archiveSkips, err := decodeTaskbookFile(archive, "archive #", true, now)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read archive: %w", err)
+		}
+		tasks = append(tasks, archived...)
+		skips = append(skips, archiveSkips...)
+	}
+	return tasks, skips, nil
+}
+
+func decodeTaskbookFile(r io.Reader, prefix string, archived bool, now time.Time) ([]domain.Task, []TaskbookSkip, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, nil, err
+	}
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	// newest first; Taskbook's keys are its item IDs
+	sort.Slice(keys, func(i, j int) bool {
+		a, aerr := strconv.Atoi(keys[i])
+		b, berr := strconv.Atoi(keys[j])
+		if aerr != nil || berr != nil {
+			return keys[i] > keys[j]
+		}
+		return a > b
+	})
+
+	var tasks []domain.Task
+	var skips []TaskbookSkip
+	for _, k := range keys {
+		var it taskbookItem
+		if err := json.Unmarshal(raw[k], &it); err != nil {
+			skips = append(skips, TaskbookSkip{Ref: prefix + k, Reason: "not a Taskbook item"})
+			continue
+		}
+		ref := prefix + k
+		desc := strings.Join(strings.Fields(it.Description), " ")
+		if desc == "" {
+			skips = append(skips, TaskbookSkip{Ref: ref, Reason: "no description"})
+			continue
+		}
+		t := domain.Task{Content: desc, IsStarred: it.IsStarred, Status: domain.TaskStatusTodo}
+		if it.Timestamp > 0 {
+			t.CreatedAt = it.Timestamp / 1000
+		}
+		for _, b := range it.Boards {
+			name := strings.TrimPrefix(strings.TrimSpace(b), "@")
+			if name == "" || name == taskbookDefaultBoard {
+				continue
+			}
+			tags, err := task.ParseTags(strings.ReplaceAll(name, " ", "-"))
+			if err != nil || len(tags) != 1 {
+				skips = append(skips, TaskbookSkip{Ref: ref, Description: desc, Reason: fmt.Sprintf("board %q not kept: not a valid tag", b)})
+				continue
+			}
+			if !containsTag(t.Tags, tags[0]) {
+				t.Tags = append(t.Tags, tags[0])
+			}
+		}
+		if it.IsTask {
+			switch {
+			case it.IsComplete:
+				t.Status = domain.TaskStatusDone
+			case it.InProgress:
+				t.Status = domain.TaskStatusInProgress
+			}
+			switch it.Priority {
+			case 2:
+				t.Priority = domain.PriorityMedium
+			case 3:
+				t.Priority = domain.PriorityHigh
+			}
+		} else if !containsTag(t.Tags, TaskbookNoteTag) {
+			t.Tags = append(t.Tags, TaskbookNoteTag)
+		}
+		if archived {
+			t.TrashedFrom = t.Status
+			t.Status = domain.TaskStatusTrashed
+			t.DeletedAt = now.Unix()
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, skips, nil
+}
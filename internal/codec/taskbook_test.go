@@ -0,0 +1,93 @@
+package codec
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+func openFixture(t *testing.T, name string) *os.File {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", "taskbook", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestDecodeTaskbook(t *testing.T) {
+	deleted := testNow.Unix()
+	storage := []domain.Task{
+		{Content: "Buy milk", Status: domain.TaskStatusTodo, CreatedAt: 1571567899},
+		{Content: "Some idea", Status: domain.TaskStatusTodo, CreatedAt: 1571567892, Tags: []string{TaskbookNoteTag}},
+		{Content: "Read docs", Status: domain.TaskStatusDone, CreatedAt: 1571567891, Priority: domain.PriorityMedium,
+			Tags: []string{"coding", "side-project"}},
+		{Content: "Fix the build", Status: domain.TaskStatusInProgress, CreatedAt: 1571567890, IsStarred: true,
+			Priority: domain.PriorityHigh},
+	}
+	archive := []domain.Task{
+		{Content: "Old note", Status: domain.TaskStatusTrashed, TrashedFrom: domain.TaskStatusTodo, CreatedAt: 1571500001,
+			IsStarred: true, Tags: []string{TaskbookNoteTag}, DeletedAt: deleted},
+		{Content: "Old task", Status: domain.TaskStatusTrashed, TrashedFrom: domain.TaskStatusDone, CreatedAt: 1571500000,
+			Tags: []string{"coding"}, DeletedAt: deleted},
+	}
+	skips := []TaskbookSkip{
+		{Ref: "#5", Reason: "not a Taskbook item"},
+		{Ref: "#4", Reason: "no description"},
+		{Ref: "#2", Description: "Read docs", Reason: `board "@bad!board" not kept: not a valid tag`},
+	}
+
+	tests := []struct {
+		name        string
+		withArchive bool
+		tasks       []domain.Task
+	}{
+		{"storage only", false, storage},
+		{"with archive", true, append(append([]domain.Task(nil), storage...), archive...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []domain.Task
+			var gotSkips []TaskbookSkip
+			var err error
+			if tt.withArchive {
+				got, gotSkips, err = DecodeTaskbook(openFixture(t, "storage/storage.json"), openFixture(t, "archive/archive.json"), testNow)
+			} else {
+				got, gotSkips, err = DecodeTaskbook(openFixture(t, "storage/storage.json"), nil, testNow)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.tasks) {
+				t.Fatalf("tasks:\ngot  %+v\nwant %+v", got, tt.tasks)
+			}
+			if !reflect.DeepEqual(gotSkips, skips) {
+				t.Fatalf("skips:\ngot  %+v\nwant %+v", gotSkips, skips)
+			}
+		})
+	}
+}
+
+func TestDecodeTaskbookErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		storage, archive string
+		want             string
+	}{
+		{"storage is not an object", `[1, 2]`, `{}`, "read storage:"},
+		{"broken archive", `{}`, `{"1":`, "read archive:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := DecodeTaskbook(strings.NewReader(tt.storage), strings.NewReader(tt.archive), testNow)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
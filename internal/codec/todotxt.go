@@ -0,0 +1,242 @@
+package codec
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+// todo.txt priorities are letters; A to D map onto urgent to low and any
+// later letter reads as low.
+var todoTxtPriorities = map[domain.Priority]byte{
+	domain.PriorityUrgent: 'A',
+	domain.PriorityHigh:   'B',
+	domain.PriorityMedium: 'C',
+	domain.PriorityLow:    'D',
+}
+
+// todoTxtDate is the date layout of todo.txt.
+const todoTxtDate = "2006-01-02"
+
+// Extension keys lazytodo reads and writes. Other key:value words are kept
+// in the title as they are.
+const (
+	todoTxtDue    = "due"
+	todoTxtPri    = "pri"
+	todoTxtStatus = "status"
+	todoTxtStar   = "star"
+	todoTxtID     = "id"
+)
+
+var (
+	todoTxtPriPattern  = regexp.MustCompile(`^\(([A-Z])\) `)
+	todoTxtDatePattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}) `)
+)
+
+// EncodeTodoTxt writes b in todo.txt format, one task per line, column by
+// column. Done tasks start with "x" and their completion date, which is
+// followed by the creation date as the format requires; a done task's
+// priority moves to a pri: extension, as todo.sh does. Projects are
+// written from tags. In-progress tasks get status:doing and starred ones
+// star:yes; with ids set, every task ends in id:<ID> so that DecodeTodoTxt
+// followed by an import updates tasks rather than adding them again.
+func EncodeTodoTxt(w io.Writer, b Board, ids bool) error {
+	bw := bufio.NewWriter(w)
+	for _, st := range Columns {
+		for _, t := range b.Tasks[st] {
+			bw.WriteString(todoTxtLine(t, ids) + "\n")
+		}
+	}
+	return bw.Flush()
+}
+
+func todoTxtLine(t domain.Task, ids bool) string {
+	var words []string
+	letter, hasPri := todoTxtPriorities[t.Priority]
+	if t.Status == domain.TaskStatusDone {
+		words = append(words, "x")
+		if done := completedAt(t); done != 0 && t.CreatedAt != 0 {
+			words = append(words, formatDate(done), formatDate(t.CreatedAt))
+		}
+	} else {
+		if hasPri {
+			words = append(words, "("+string(letter)+")")
+		}
+		if t.CreatedAt != 0 {
+			words = append(words, formatDate(t.CreatedAt))
+		}
+	}
+	words = append(words, t.Content)
+	for _, tag := range t.Tags {
+		words = append(words, "+"+tag)
+	}
+	if t.DueAt != 0 {
+		words = append(words, todoTxtDue+":"+task.FormatDue(t.DueAt))
+	}
+	if t.Status == domain.TaskStatusDone && hasPri {
+		words = append(words, todoTxtPri+":"+string(letter))
+	}
+	if t.Status == domain.TaskStatusInProgress {
+		words = append(words, todoTxtStatus+":doing")
+	}
+	if t.IsStarred {
+		words = append(words, todoTxtStar+":yes")
+	}
+	if ids {
+		words = append(words, todoTxtID+":"+t.Id)
+	}
+	return strings.Join(words, " ")
+}
+
+// completedAt is when t last entered Done, falling back to its last update.
+func completedAt(t domain.Task) int64 {
+	for i := len(t.StatusHistory) - 1; i >= 0; i-- {
+		if t.StatusHistory[i].Status == domain.TaskStatusDone {
+			return t.StatusHistory[i].At
+		}
+	}
+	return t.UpdatedAt
+}
+
+func formatDate(at int64) string { return time.Unix(at, 0).Format(todoTxtDate) }
+
+// DecodeTodoTxt reads tasks from todo.txt lines. Blank lines are skipped.
+// +project words become tags and @context words stay in the title, where
+// they can be searched for. A completion date becomes the time the task
+// entered Done, and the creation date its CreatedAt. Tasks without an id:
+// extension get an empty Id.
+func DecodeTodoTxt(r io.Reader, now time.Time) ([]domain.Task, error) {
+	var out []domain.Task
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		t, err := parseTodoTxtLine(line, now)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		out = append(out, t)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read todo.txt: %w", err)
+	}
+	return out, nil
+}
+
+func parseTodoTxtLine(line string, now time.Time) (domain.Task, error) {
+	t := domain.Task{Status: domain.TaskStatusTodo}
+	var done int64
+	if strings.HasPrefix(line, "x ") {
+		t.Status = domain.TaskStatusDone
+		line = strings.TrimLeft(line[2:], " ")
+		// "x <done> <created>"; a single date is the completion date
+		if at, rest, ok := cutDate(line); ok {
+			done, line = at, rest
+			if at, rest, ok := cutDate(line); ok {
+				t.CreatedAt, line = at, rest
+			}
+		}
+	} else {
+		if m := todoTxtPriPattern.FindStringSubmatch(line); m != nil {
+			t.Priority = priorityFromLetter(m[1][0])
+			line = strings.TrimLeft(line[len(m[0]):], " ")
+		}
+		if at, rest, ok := cutDate(line); ok {
+			t.CreatedAt, line = at, rest
+		}
+	}
+
+	var title []string
+	for _, w := range strings.Fields(line) {
+		if len(w) > 1 && w[0] == '+' {
+			if tags, err := task.ParseTags(w[1:]); err == nil && len(tags) == 1 {
+				if !containsTag(t.Tags, tags[0]) {
+					t.Tags = append(t.Tags, tags[0])
+				}
+				continue
+			}
+		}
+		key, value, ok := strings.Cut(w, ":")
+		if !ok || value == "" {
+			title = append(title, w)
+			continue
+		}
+		switch strings.ToLower(key) {
+		case todoTxtDue:
+			due, set, err := task.ParseDue(value, now)
+			if err != nil {
+				return domain.Task{}, err
+			}
+			if set {
+				t.DueAt = due.Unix()
+			}
+		case todoTxtPri:
+			if len(value) != 1 || value[0] < 'A' || value[0] > 'Z' {
+				return domain.Task{}, fmt.Errorf("invalid priority %q: use a letter A-Z", value)
+			}
+			t.Priority = priorityFromLetter(value[0])
+		case todoTxtStatus:
+			if strings.EqualFold(value, "doing") && t.Status != domain.TaskStatusDone {
+				t.Status = domain.TaskStatusInProgress
+			}
+		case todoTxtStar:
+			t.IsStarred = strings.EqualFold(value, "yes")
+		case todoTxtID:
+			t.Id = value
+		default:
+			title = append(title, w)
+		}
+	}
+	t.Content = strings.Join(title, " ")
+	if t.Content == "" {
+		return domain.Task{}, fmt.Errorf("task has no text")
+	}
+	if t.Status == domain.TaskStatusDone && done != 0 {
+		t.StatusHistory = []domain.StatusChange{{Status: domain.TaskStatusDone, At: done}}
+		t.UpdatedAt = done
+	}
+	if t.Status == domain.TaskStatusInProgress {
+		t.StartedAt = t.CreatedAt
+	}
+	return t, nil
+}
+
+// cutDate splits a leading YYYY-MM-DD date off s.
+func cutDate(s string) (int64, string, bool) {
+	m := todoTxtDatePattern.FindStringSubmatch(s + " ")
+	if m == nil {
+		return 0, s, false
+	}
+	d, err := time.ParseInLocation(todoTxtDate, m[1], time.Local)
+	if err != nil {
+		return 0, s, false
+	}
+	return d.Unix(), strings.TrimLeft(s[len(m[1]):], " "), true
+}
+
+func priorityFromLetter(c byte) domain.Priority {
+	for p, letter := range todoTxtPriorities {
+		if letter == c {
+			return p
+		}
+	}
+	return domain.PriorityLow
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package codec
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+// day returns the start of a local day as Unix time.
+func day(y int, m time.Month, d int) int64 {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local).Unix()
+}
+
+var testNow = time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local)
+
+func TestDecodeTodoTxt(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want domain.Task
+	}{
+		{
+			name: "priority, creation date, project, context and due",
+			line: "(A) 2026-09-01 Call mom @phone +family due:2026-10-20",
+			want: domain.Task{Content: "Call mom @phone", Priority: domain.PriorityUrgent, CreatedAt: day(2026, 9, 1),
+				Tags: []string{"family"}, DueAt: day(2026, 10, 20)},
+		},
+		{
+			name: "done with completion and creation dates",
+			line: "x 2026-10-10 2026-09-02 File taxes +finance pri:B",
+			want: domain.Task{Content: "File taxes", Status: domain.TaskStatusDone, Priority: domain.PriorityHigh,
+				CreatedAt: day(2026, 9, 2), UpdatedAt: day(2026, 10, 10), Tags: []string{"finance"},
+				StatusHistory: []domain.StatusChange{{Status: domain.TaskStatusDone, At: day(2026, 10, 10)}}},
+		},
+		{
+			name: "done with a single date, which is the completion date",
+			line: "x 2026-10-10 Old thing",
+			want: domain.Task{Content: "Old thing", Status: domain.TaskStatusDone, UpdatedAt: day(2026, 10, 10),
+				StatusHistory: []domain.StatusChange{{Status: domain.TaskStatusDone, At: day(2026, 10, 10)}}},
+		},
+		{
+			name: "lazytodo extensions; other key:value words stay",
+			line: "2026-09-03 Write report status:doing star:yes id:42 url:http://example.com",
+			want: domain.Task{Id: "42", Content: "Write report url:http://example.com", Status: domain.TaskStatusInProgress,
+				IsStarred: true, CreatedAt: day(2026, 9, 3), StartedAt: day(2026, 9, 3)},
+		},
+		{
+			name: "letters after D read as low",
+			line: "(Z) Sweep floor",
+			want: domain.Task{Content: "Sweep floor", Priority: domain.PriorityLow},
+		},
+		{
+			name: "lower-case priority is text",
+			line: "(a) not a priority",
+			want: domain.Task{Content: "(a) not a priority"},
+		},
+		{
+			name: "projects are tags once; numeric ones stay in the text",
+			line: "Fix +web issue +123 +web",
+			want: domain.Task{Content: "Fix issue +123", Tags: []string{"web"}},
+		},
+		{
+			name: "x without a space is text",
+			line: "xylophone lesson",
+			want: domain.Task{Content: "xylophone lesson"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeTodoTxt(strings.NewReader(tt.line+"\n"), testNow)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 1 {
+				t.Fatalf("got %d tasks, want 1", len(got))
+			}
+			if !reflect.DeepEqual(got[0], tt.want) {
+				t.Fatalf("got  %+v\nwant %+v", got[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeTodoTxtErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Call bob due:nope", "line 1: invalid due date"},
+		{"one\n\nx pri:9 two", "line 3: invalid priority"},
+		{"(A) +project due:2026-10-10", "line 1: task has no text"},
+	}
+	for _, tt := range tests {
+		_, err := DecodeTodoTxt(strings.NewReader(tt.input), testNow)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: error %v, want it to contain %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestTodoTxtRoundTrip(t *testing.T) {
+	tasks := []domain.Task{
+		{Id: "1", Content: "Ship release", Status: domain.TaskStatusTodo, IsStarred: true,
+			Priority: domain.PriorityHigh, Tags: []string{"ops", "release"}, DueAt: day(2026, 10, 23),
+			CreatedAt: day(2026, 10, 1)},
+		{Id: "2", Content: "Call mom @phone", Status: domain.TaskStatusTodo, Priority: domain.PriorityUrgent},
+		{Id: "3", Content: "Write report", Status: domain.TaskStatusInProgress, CreatedAt: day(2026, 9, 3),
+			StartedAt: day(2026, 9, 3)},
+		{Id: "4", Content: "File taxes", Status: domain.TaskStatusDone, Priority: domain.PriorityLow,
+			CreatedAt: day(2026, 9, 2), UpdatedAt: day(2026, 10, 10),
+			StatusHistory: []domain.StatusChange{{Status: domain.TaskStatusDone, At: day(2026, 10, 10)}}},
+	}
+	board := Board{Tasks: map[domain.TaskStatus][]domain.Task{}}
+	for _, task := range tasks {
+		board.Tasks[task.Status] = append(board.Tasks[task.Status], task)
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeTodoTxt(&buf, board, true); err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeTodoTxt(&buf, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, tasks) {
+		t.Fatalf("round trip changed the tasks:\ngot  %+v\nwant %+v", got, tasks)
+	}
+}
+
+func TestEncodeTodoTxt(t *testing.T) {
+	board := Board{Tasks: map[domain.TaskStatus][]domain.Task{
+		domain.TaskStatusTodo: {{Id: "1", Content: "Ship", Priority: domain.PriorityMedium, Tags: []string{"ops"},
+			CreatedAt: day(2026, 10, 1), Notes: "not exported", Subtasks: []domain.Subtask{{Text: "nor this"}}}},
+		domain.TaskStatusDone: {{Id: "2", Content: "Old", Status: domain.TaskStatusDone, CreatedAt: day(2026, 9, 1), UpdatedAt: day(2026, 9, 5)}},
+	}}
+	var buf bytes.Buffer
+	if err := EncodeTodoTxt(&buf, board, false); err != nil {
+		t.Fatal(err)
+	}
+	want := "(C) 2026-10-01 Ship +ops\nx 2026-09-05 2026-09-01 Old\n"
+	if buf.String() != want {
+		t.Fatalf("got %q, want %q", buf.String(), want)
+	}
+}
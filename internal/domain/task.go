@@ -6,14 +6,58 @@ const (
 	TaskStatusTodo TaskStatus = iota
 	TaskStatusInProgress
 	TaskStatusDone
+	// TaskStatusTrashed holds deleted tasks until they are restored or purged.
+	TaskStatusTrashed
 )
 
+// Priority ranks tasks; higher values sort first.
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+// Subtask is one item of a task's checklist.
+type Subtask struct {
+	Text string
+	Done bool
+}
+
+// StatusChange records when a task entered a status.
+type StatusChange struct {
+	Status TaskStatus
+	At     int64
+}
+
 type Task struct {
-	Id        string
-	Content   string
+	Id      string
+	Content string
+	// Notes is free-form markdown shown in the detail pane.
+	Notes     string
 	Status    TaskStatus
 	IsStarred bool
+	Priority  Priority
+	// Tags are lower-case names without the leading '#'. The slice is
+	// replaced, never modified in place, so copies of a Task may share it.
+	Tags []string
+	// Subtasks is the checklist, in order. Like Tags it is replaced, never
+	// modified in place.
+	Subtasks []Subtask
+	// Rank orders the column in manual sort mode; lower ranks come first.
+	Rank      int64
 	StartedAt int64
 	CreatedAt int64
 	UpdatedAt int64
+	// DueAt is the start of the local day the task is due, zero if none.
+	DueAt int64
+	// StatusHistory lists the statuses the task went through, oldest
+	// first. Like Tags it is replaced, never modified in place.
+	StatusHistory []StatusChange
+	// DeletedAt and TrashedFrom are set while the task is in the trash.
+	DeletedAt   int64
+	TrashedFrom TaskStatus
 }
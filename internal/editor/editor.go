@@ -0,0 +1,41 @@
+// Package editor starts the user's text editor.
+package editor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Command returns a command that runs $VISUAL, else $EDITOR, else vi on
+// path. The variable may carry arguments, as in "code --wait".
+func Command(path string) *exec.Cmd {
+	name := os.Getenv("VISUAL")
+	if strings.TrimSpace(name) == "" {
+		name = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(name)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
+// TempFile writes content to a new temporary markdown file and returns its
+// path. The caller removes it.
+func TempFile(content string) (string, error) {
+	f, err := os.CreateTemp("", "lazytodo-*.md")
+	if err != nil {
+		return "", fmt.Errorf("create edit file: %w", err)
+	}
+	_, err = f.WriteString(content)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("write edit file: %w", err)
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,55 @@
+// Package fuzzy implements the subsequence matching used by search.
+package fuzzy
+
+import "unicode"
+
+// Match reports whether every rune of pattern occurs in text in order,
+// ignoring case, and returns the rune indexes in text that matched. Matches
+// prefer the start of words, so "ld" finds the "l" of "login docs" rather
+// than the one in "build".
+func Match(pattern, text string) ([]int, bool) {
+	p := []rune(pattern)
+	if len(p) == 0 {
+		return nil, true
+	}
+	t := []rune(text)
+	if pos, ok := match(p, t, true); ok {
+		return pos, true
+	}
+	return match(p, t, false)
+}
+
+// match greedily matches p in t. With wordStarts set, each rune is first
+// looked for at the start of a word before anywhere.
+func match(p, t []rune, wordStarts bool) ([]int, bool) {
+	pos := make([]int, 0, len(p))
+	from := 0
+	for _, pr := range p {
+		i := -1
+		if wordStarts {
+			i = find(t, from, pr, true)
+		}
+		if i == -1 {
+			i = find(t, from, pr, false)
+		}
+		if i == -1 {
+			return nil, false
+		}
+		pos = append(pos, i)
+		from = i + 1
+	}
+	return pos, true
+}
+
+func find(t []rune, from int, r rune, wordStart bool) int {
+	for i := from; i < len(t); i++ {
+		if unicode.ToLower(t[i]) != unicode.ToLower(r) {
+			continue
+		}
+		if wordStart && i > 0 && unicode.IsLetter(t[i-1]) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
@@ -0,0 +1,297 @@
+// Package query parses and evaluates board filters such as
+//
+//	status:todo tag:backend due:<7d -tag:blocked starred
+//
+// A query is a list of terms separated by spaces; a task must match every
+// term. The word "or" splits a query into alternatives, any of which may
+// match. A leading "-" negates a term. Terms:
+//
+//	status:todo|doing|done   column (in_progress and progress also work)
+//	tag:name, #name          carries the tag
+//	due:<expr>               due on that day (see task.ParseDue)
+//	due:<7d, due:>=fri       due before/after a day; 7d, 2w, 1m count from today
+//	due:overdue|today|any|none
+//	priority:high, priority:>=medium
+//	starred, is:starred
+//	word, "some words"       content contains the text, ignoring case
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+// Query is a parsed filter.
+type Query struct {
+	src    string
+	groups [][]term
+}
+
+type predicate func(t domain.Task, now time.Time) bool
+
+type term struct {
+	neg   bool
+	match predicate
+}
+
+// Parse reads a query. An empty query matches every task.
+func Parse(src string) (*Query, error) {
+	words, err := split(src)
+	if err != nil {
+		return nil, err
+	}
+	q := &Query{src: strings.TrimSpace(src)}
+	var group []term
+	for _, w := range words {
+		if !w.quoted && strings.EqualFold(w.text, "or") {
+			if len(group) == 0 {
+				return nil, errors.New(`"or" needs a term on each side`)
+			}
+			q.groups = append(q.groups, group)
+			group = nil
+			continue
+		}
+		t, err := parseTerm(w)
+		if err != nil {
+			return nil, err
+		}
+		group = append(group, t)
+	}
+	if len(group) == 0 && len(q.groups) > 0 {
+		return nil, errors.New(`"or" needs a term on each side`)
+	}
+	if len(group) > 0 {
+		q.groups = append(q.groups, group)
+	}
+	return q, nil
+}
+
+// String returns the query as it was written.
+func (q *Query) String() string { return q.src }
+
+// Match reports whether t passes the query; relative dates are taken from
+// now.
+func (q *Query) Match(t domain.Task, now time.Time) bool {
+	if len(q.groups) == 0 {
+		return true
+	}
+	for _, g := range q.groups {
+		if matchAll(g, t, now) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAll(terms []term, t domain.Task, now time.Time) bool {
+	for _, tm := range terms {
+		if tm.match(t, now) == tm.neg {
+			return false
+		}
+	}
+	return true
+}
+
+type word struct {
+	text   string
+	quoted bool
+	neg    bool
+}
+
+// split breaks src into words, keeping double-quoted text together.
+func split(src string) ([]word, error) {
+	var out []word
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+		var w word
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			w.neg = true
+			i++
+		}
+		if runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("unterminated quote")
+			}
+			w.text, w.quoted = string(runes[i+1:end]), true
+			i = end + 1
+		} else {
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) {
+				i++
+			}
+			w.text = string(runes[start:i])
+		}
+		out = append(out, w)
+	}
+	return out, nil
+}
+
+func parseTerm(w word) (term, error) {
+	if w.quoted {
+		return term{neg: w.neg, match: contains(w.text)}, nil
+	}
+	text := w.text
+	if strings.EqualFold(text, "starred") {
+		return term{neg: w.neg, match: starred}, nil
+	}
+	if strings.HasPrefix(text, "#") && len(text) > 1 {
+		return term{neg: w.neg, match: hasTag(text[1:])}, nil
+	}
+	key, value, ok := strings.Cut(text, ":")
+	if !ok {
+		return term{neg: w.neg, match: contains(text)}, nil
+	}
+	var (
+		p   predicate
+		err error
+	)
+	switch strings.ToLower(key) {
+	case "status":
+		p, err = statusTerm(value)
+	case "tag":
+		if value == "" {
+			return term{}, errors.New("tag: needs a name")
+		}
+		p = hasTag(strings.TrimPrefix(value, "#"))
+	case "due":
+		p, err = dueTerm(value)
+	case "priority", "prio":
+		p, err = priorityTerm(value)
+	case "is":
+		if !strings.EqualFold(value, "starred") {
+			return term{}, fmt.Errorf("unknown is:%s", value)
+		}
+		p = starred
+	default:
+		return term{}, fmt.Errorf("unknown field %q", key)
+	}
+	if err != nil {
+		return term{}, err
+	}
+	return term{neg: w.neg, match: p}, nil
+}
+
+func contains(text string) predicate {
+	text = strings.ToLower(text)
+	return func(t domain.Task, _ time.Time) bool {
+		return strings.Contains(strings.ToLower(t.Content), text)
+	}
+}
+
+func starred(t domain.Task, _ time.Time) bool { return t.IsStarred }
+
+func hasTag(name string) predicate {
+	name = strings.ToLower(name)
+	return func(t domain.Task, _ time.Time) bool {
+		for _, tag := range t.Tags {
+			if tag == name {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+var statusValues = map[string]domain.TaskStatus{
+	"todo":        domain.TaskStatusTodo,
+	"doing":       domain.TaskStatusInProgress,
+	"progress":    domain.TaskStatusInProgress,
+	"in_progress": domain.TaskStatusInProgress,
+	"done":        domain.TaskStatusDone,
+}
+
+func statusTerm(value string) (predicate, error) {
+	st, ok := statusValues[strings.ToLower(value)]
+	if !ok {
+		return nil, fmt.Errorf("unknown status %q", value)
+	}
+	return func(t domain.Task, _ time.Time) bool { return t.Status == st }, nil
+}
+
+// cutOp splits a comparison operator off the front of value.
+func cutOp(value string) (op, rest string) {
+	for _, op := range []string{"<=", ">=", "<", ">", "="} {
+		if strings.HasPrefix(value, op) {
+			return op, value[len(op):]
+		}
+	}
+	return "=", value
+}
+
+func compare(op string, a, b int64) bool {
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+	return a == b
+}
+
+func dueTerm(value string) (predicate, error) {
+	switch strings.ToLower(value) {
+	case "none":
+		return func(t domain.Task, _ time.Time) bool { return t.DueAt == 0 }, nil
+	case "any":
+		return func(t domain.Task, _ time.Time) bool { return t.DueAt != 0 }, nil
+	case "overdue":
+		return func(t domain.Task, now time.Time) bool {
+			state, _ := task.DueIn(t.DueAt, now)
+			return state == task.DueOverdue
+		}, nil
+	}
+	op, expr := cutOp(value)
+	if expr != "" && unicode.IsDigit(rune(expr[0])) && strings.ContainsAny(expr[len(expr)-1:], "dwm") {
+		expr = "+" + expr
+	}
+	// check the expression once up front so typos fail at parse time
+	if _, ok, err := task.ParseDue(expr, time.Now()); err != nil || !ok {
+		if err == nil {
+			err = fmt.Errorf("invalid due date %q", expr)
+		}
+		return nil, err
+	}
+	return func(t domain.Task, now time.Time) bool {
+		if t.DueAt == 0 {
+			return false
+		}
+		day, _, _ := task.ParseDue(expr, now)
+		due := time.Unix(t.DueAt, 0).In(now.Location())
+		y, m, d := due.Date()
+		return compare(op, time.Date(y, m, d, 0, 0, 0, 0, now.Location()).Unix(), day.Unix())
+	}, nil
+}
+
+func priorityTerm(value string) (predicate, error) {
+	op, name := cutOp(value)
+	want := domain.Priority(-1)
+	for p := domain.PriorityNone; p <= domain.PriorityUrgent; p++ {
+		if strings.EqualFold(task.PriorityLabel(p), name) {
+			want = p
+		}
+	}
+	if want < 0 {
+		return nil, fmt.Errorf("unknown priority %q", name)
+	}
+	return func(t domain.Task, _ time.Time) bool {
+		return compare(op, int64(t.Priority), int64(want))
+	}, nil
+}
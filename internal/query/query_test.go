@@ -0,0 +1,110 @@
+package query
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+var now = time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local)
+
+func day(m time.Month, d int) int64 {
+	return time.Date(2026, m, d, 0, 0, 0, 0, time.Local).Unix()
+}
+
+var board = []domain.Task{
+	{Content: "Fix login bug", Status: domain.TaskStatusTodo, Tags: []string{"backend"},
+		DueAt: day(10, 16), Priority: domain.PriorityHigh},
+	{Content: "Write docs", Status: domain.TaskStatusInProgress, Tags: []string{"docs"},
+		DueAt: day(10, 17), IsStarred: true},
+	{Content: "Deploy", Status: domain.TaskStatusDone, Tags: []string{"backend", "blocked"},
+		DueAt: day(10, 30), Priority: domain.PriorityUrgent},
+	{Content: "Plan trip", Status: domain.TaskStatusTodo, Priority: domain.PriorityLow},
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Fix login bug", "Write docs", "Deploy", "Plan trip"}},
+		{"status:todo", []string{"Fix login bug", "Plan trip"}},
+		{"status:doing", []string{"Write docs"}},
+		{"status:in_progress", []string{"Write docs"}},
+		{"tag:backend", []string{"Fix login bug", "Deploy"}},
+		{"#backend", []string{"Fix login bug", "Deploy"}},
+		{"tag:#BACKEND", []string{"Fix login bug", "Deploy"}},
+		{"tag:backend -tag:blocked", []string{"Fix login bug"}},
+		{"due:overdue", []string{"Fix login bug"}},
+		{"due:today", []string{"Write docs"}},
+		{"due:<7d", []string{"Fix login bug", "Write docs"}},
+		{"due:>=2026-10-20", []string{"Deploy"}},
+		{"due:none", []string{"Plan trip"}},
+		{"due:any", []string{"Fix login bug", "Write docs", "Deploy"}},
+		{"priority:high", []string{"Fix login bug"}},
+		{"priority:>=high", []string{"Fix login bug", "Deploy"}},
+		{"prio:<medium", []string{"Write docs", "Plan trip"}},
+		{"starred", []string{"Write docs"}},
+		{"is:starred", []string{"Write docs"}},
+		{"-starred", []string{"Fix login bug", "Deploy", "Plan trip"}},
+		{"LOGIN", []string{"Fix login bug"}},
+		{`"write docs"`, []string{"Write docs"}},
+		{`-"plan"`, []string{"Fix login bug", "Write docs", "Deploy"}},
+		{"status:todo or starred", []string{"Fix login bug", "Write docs", "Plan trip"}},
+		{"tag:docs OR tag:blocked", []string{"Write docs", "Deploy"}},
+		{`"or"`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, task := range board {
+				if q.Match(task, now) {
+					got = append(got, task.Content)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("matched %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"status:nope", "unknown status"},
+		{"tag:", "needs a name"},
+		{"due:whenever", "due"},
+		{"priority:huge", "unknown priority"},
+		{"or starred", `"or" needs a term on each side`},
+		{"starred or", `"or" needs a term on each side`},
+		{`"unterminated`, "unterminated quote"},
+		{"color:red", "unknown field"},
+		{"is:open", "unknown is:open"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.query)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q): error %v, want it to contain %q", tt.query, err, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	q, err := Parse("  status:todo  starred ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.String() != "status:todo  starred" {
+		t.Fatalf("String() = %q", q.String())
+	}
+}
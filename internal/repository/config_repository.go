@@ -2,8 +2,40 @@ package repository
 
 type Config struct {
 	Vertical bool `json:"vertical"`
+	// Backups is the number of task file backups to keep. Zero means the
+	// store default; a negative value disables backups.
+	Backups int `json:"backups,omitempty"`
+	// Storage selects the task backend: StorageJSON (the default) or
+	// StorageSQLite.
+	Storage string `json:"storage,omitempty"`
+	// Boards lists the named boards besides DefaultBoard.
+	Boards []string `json:"boards,omitempty"`
+	// Board is the board opened on start; empty means DefaultBoard.
+	Board string `json:"board,omitempty"`
+	// HistoryLimit caps the undo history; zero means the service default.
+	HistoryLimit int `json:"history_limit,omitempty"`
+	// PersistHistory keeps the undo history across restarts.
+	PersistHistory bool `json:"persist_history,omitempty"`
+	// TrashRetentionDays is how long deleted tasks stay in the trash. Zero
+	// means the service default; a negative value keeps them forever.
+	TrashRetentionDays int `json:"trash_retention_days,omitempty"`
+	// Sort is the column order: "due" for nearest deadline first, "manual"
+	// for the order set by hand, empty for the default (priority, starred,
+	// then newest).
+	Sort string `json:"sort,omitempty"`
+	// TagColors overrides the color of a tag chip by tag name. Values are
+	// ANSI color numbers ("12") or hex colors ("#ff8800").
+	TagColors map[string]string `json:"tag_colors,omitempty"`
+	// Queries are saved board filters by name, written in the query
+	// language of package query.
+	Queries map[string]string `json:"queries,omitempty"`
 }
 
+const (
+	StorageJSON   = "json"
+	StorageSQLite = "sqlite"
+)
+
 type ConfigRepository interface {
 	Load() (Config, error)
 	Save(Config) error
@@ -0,0 +1,112 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const backupsDirName = "backups"
+
+// DefaultMaxBackups is the number of backups kept when the config does not
+// say otherwise.
+const DefaultMaxBackups = 10
+
+const backupTimeFormat = "20060102T150405.000000000"
+
+// backupPrefix returns the file name prefix used for backups of path,
+// e.g. "tasks-" for tasks.json.
+func backupPrefix(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base)) + "-"
+}
+
+// createBackup copies the current content of path into dir under a
+// timestamped name and prunes old backups so that at most max are kept.
+// A missing source file is not an error; max <= 0 disables backups.
+func createBackup(path, dir string, max int) error {
+	if max <= 0 {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("read file for backup: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create backups dir: %w", err)
+	}
+	name := backupPrefix(path) + time.Now().Format(backupTimeFormat) + filepath.Ext(path)
+	if err := writeFileAtomic(filepath.Join(dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+	return pruneBackups(path, dir, max)
+}
+
+// listBackups returns the backups of path found in dir, newest first.
+func listBackups(path, dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read backups dir: %w", err)
+	}
+	prefix := backupPrefix(path)
+	ext := filepath.Ext(path)
+	var out []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		out = append(out, filepath.Join(dir, name))
+	}
+	// timestamps are fixed width, so lexical order is chronological
+	sort.Sort(sort.Reverse(sort.StringSlice(out)))
+	return out, nil
+}
+
+func pruneBackups(path, dir string, max int) error {
+	backups, err := listBackups(path, dir)
+	if err != nil {
+		return err
+	}
+	for i := max; i < len(backups); i++ {
+		if err := os.Remove(backups[i]); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("remove old backup: %w", err)
+		}
+	}
+	return nil
+}
+
+// backupBeforeMigration keeps the pre-migration content of path regardless
+// of the backup setting. Its name does not match the rolling backups, so it
+// is never pruned or picked up by the corrupt-file fallback.
+func backupBeforeMigration(path, dir string, data []byte, version int) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create backups dir: %w", err)
+	}
+	name := fmt.Sprintf("premigration-v%d-%s%s%s", version, backupPrefix(path), time.Now().Format(backupTimeFormat), filepath.Ext(path))
+	if err := writeFileAtomic(filepath.Join(dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("write pre-migration backup: %w", err)
+	}
+	return nil
+}
+
+// quarantine moves a file that failed to decode out of the way so that the
+// next save does not back it up over a good copy.
+func quarantine(path string) error {
+	dst := path + ".corrupt-" + time.Now().Format(backupTimeFormat)
+	if err := os.Rename(path, dst); err != nil {
+		return fmt.Errorf("quarantine corrupt file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreateBackupRotates(t *testing.T) {
+	tests := []struct {
+		name  string
+		max   int
+		saves int
+		want  int
+	}{
+		{"disabled", 0, 3, 0},
+		{"below the limit", 3, 2, 2},
+		{"at the limit", 3, 3, 3},
+		{"past the limit", 3, 7, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "tasks.json")
+			backups := filepath.Join(dir, backupsDirName)
+			for i := 0; i < tt.saves; i++ {
+				if err := os.WriteFile(path, []byte(fmt.Sprint(i)), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				if err := createBackup(path, backups, tt.max); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := listBackups(path, backups)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.want {
+				t.Fatalf("%d backups, want %d", len(got), tt.want)
+			}
+			// the newest backups are the ones kept
+			for i, b := range got {
+				data, err := os.ReadFile(b)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if want := fmt.Sprint(tt.saves - 1 - i); string(data) != want {
+					t.Fatalf("backup %d holds %q, want %q", i, data, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateBackupMissingSource(t *testing.T) {
+	dir := t.TempDir()
+	if err := createBackup(filepath.Join(dir, "tasks.json"), filepath.Join(dir, backupsDirName), 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, backupsDirName)); !os.IsNotExist(err) {
+		t.Fatalf("stat: %v, want no backups dir", err)
+	}
+}
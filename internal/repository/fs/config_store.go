@@ -1,6 +1,7 @@
 package fs
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,24 +14,17 @@ import (
 
 const configFileName = "config.json"
 
-type ConfigStore struct{}
-
-func NewConfigStore() *ConfigStore { return &ConfigStore{} }
+type ConfigStore struct {
+	path string
+}
 
-func configFilePath() (string, error) {
-	dir, err := defaultDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(dir, configFileName), nil
+// NewConfigStore returns a store for dir/config.json.
+func NewConfigStore(dir string) *ConfigStore {
+	return &ConfigStore{path: filepath.Join(dir, configFileName)}
 }
 
 func (s *ConfigStore) Load() (repository.Config, error) {
-	path, err := configFilePath()
-	if err != nil {
-		return repository.Config{}, err
-	}
-	data, err := os.ReadFile(path)
+	data, err := os.ReadFile(s.path)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return repository.Config{Vertical: false}, nil
@@ -45,18 +39,18 @@ func (s *ConfigStore) Load() (repository.Config, error) {
 }
 
 func (s *ConfigStore) Save(cfg repository.Config) error {
-	if err := ensureDirExists(); err != nil {
-		return err
-	}
-	path, err := configFilePath()
-	if err != nil {
-		return err
-	}
-	b, err := json.MarshalIndent(cfg, "", "  ")
-	if err != nil {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+	// saved queries use < and >, which would otherwise be escaped
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(cfg); err != nil {
 		return fmt.Errorf("encode config: %w", err)
 	}
-	if err := os.WriteFile(path, b, 0o644); err != nil {
+	if err := os.WriteFile(s.path, buf.Bytes(), 0o644); err != nil {
 		return fmt.Errorf("write config: %w", err)
 	}
 	return nil
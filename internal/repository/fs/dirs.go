@@ -0,0 +1,84 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hungtrd/lazytodo/internal/repository"
+)
+
+const (
+	legacyDirName = ".lazytodo"
+	xdgAppName    = "lazytodo"
+
+	// EnvHome overrides the data and config directory.
+	EnvHome = "LAZYTODO_HOME"
+)
+
+// Dirs holds where tasks and config live. They are the same directory
+// unless XDG base directories are in use.
+type Dirs struct {
+	Data   string
+	Config string
+}
+
+// ResolveDirs picks the directories to use, in order of precedence:
+//
+//  1. override, normally from the --data-dir flag
+//  2. $LAZYTODO_HOME
+//  3. $XDG_DATA_HOME/lazytodo and $XDG_CONFIG_HOME/lazytodo, each if set
+//  4. ~/.lazytodo
+//
+// An XDG directory that does not exist yet is skipped while ~/.lazytodo
+// exists, so setting XDG variables does not hide an existing board.
+func ResolveDirs(override string) (Dirs, error) {
+	if override != "" {
+		return Dirs{Data: override, Config: override}, nil
+	}
+	if home := os.Getenv(EnvHome); home != "" {
+		return Dirs{Data: home, Config: home}, nil
+	}
+	legacy, err := LegacyDir()
+	if err != nil {
+		return Dirs{}, err
+	}
+	legacyExists := dirExists(legacy)
+	pick := func(env string) string {
+		base := os.Getenv(env)
+		if base == "" {
+			return legacy
+		}
+		dir := filepath.Join(base, xdgAppName)
+		if legacyExists && !dirExists(dir) {
+			return legacy
+		}
+		return dir
+	}
+	return Dirs{Data: pick("XDG_DATA_HOME"), Config: pick("XDG_CONFIG_HOME")}, nil
+}
+
+// LegacyDir returns ~/.lazytodo.
+func LegacyDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home dir: %w", err)
+	}
+	return filepath.Join(home, legacyDirName), nil
+}
+
+func dirExists(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.IsDir()
+}
+
+const boardsDirName = "boards"
+
+// BoardDir returns the directory of a named board inside dataDir. The
+// default board lives in dataDir itself so existing data keeps working.
+func BoardDir(dataDir, name string) string {
+	if name == "" || name == repository.DefaultBoard {
+		return dataDir
+	}
+	return filepath.Join(dataDir, boardsDirName, name)
+}
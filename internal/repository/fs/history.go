@@ -0,0 +1,171 @@
+package fs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hungtrd/lazytodo/internal/repository"
+)
+
+const historyVersion = 1
+
+type historyFile struct {
+	Version int             `json:"version"`
+	Undo    []historyRecord `json:"undo"`
+	Redo    []historyRecord `json:"redo"`
+}
+
+type historyRecord struct {
+	Op      string         `json:"op"`
+	At      int64          `json:"at"`
+	Changes []changeRecord `json:"changes"`
+}
+
+type changeRecord struct {
+	Before      *taskRecord `json:"before,omitempty"`
+	After       *taskRecord `json:"after,omitempty"`
+	BeforeIndex int         `json:"before_index"`
+	AfterIndex  int         `json:"after_index"`
+}
+
+// historyPath puts the history next to the tasks file, e.g.
+// tasks.history.json for tasks.json.
+func (s *TaskStore) historyPath() string {
+	return strings.TrimSuffix(s.path, filepath.Ext(s.path)) + ".history.json"
+}
+
+func (s *TaskStore) LoadHistory() (repository.History, error) {
+	data, err := os.ReadFile(s.historyPath())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return repository.History{}, nil
+		}
+		return repository.History{}, fmt.Errorf("read history: %w", err)
+	}
+	var f historyFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return repository.History{}, fmt.Errorf("decode history: %w", err)
+	}
+	if f.Version > historyVersion {
+		return repository.History{}, fmt.Errorf("history file version %d is newer than supported version %d", f.Version, historyVersion)
+	}
+	undo, err := historyFromRecords(f.Undo)
+	if err != nil {
+		return repository.History{}, err
+	}
+	redo, err := historyFromRecords(f.Redo)
+	if err != nil {
+		return repository.History{}, err
+	}
+	return repository.History{Undo: undo, Redo: redo}, nil
+}
+
+func (s *TaskStore) SaveHistory(h repository.History) error {
+	undo, err := historyToRecords(h.Undo)
+	if err != nil {
+		return err
+	}
+	redo, err := historyToRecords(h.Redo)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(historyFile{Version: historyVersion, Undo: undo, Redo: redo}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create data dir: %w", err)
+	}
+	if err := writeFileAtomic(s.historyPath(), data, 0o644); err != nil {
+		return fmt.Errorf("write history: %w", err)
+	}
+	return nil
+}
+
+// MarshalHistoryEntry encodes one history entry in the record format of the
+// history file, so other backends store entries the same way.
+func MarshalHistoryEntry(e repository.HistoryEntry) ([]byte, error) {
+	records, err := historyToRecords([]repository.HistoryEntry{e})
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(records[0])
+	if err != nil {
+		return nil, fmt.Errorf("encode history: %w", err)
+	}
+	return data, nil
+}
+
+// UnmarshalHistoryEntry decodes an entry written by MarshalHistoryEntry.
+func UnmarshalHistoryEntry(data []byte) (repository.HistoryEntry, error) {
+	var r historyRecord
+	if err := json.Unmarshal(data, &r); err != nil {
+		return repository.HistoryEntry{}, fmt.Errorf("decode history: %w", err)
+	}
+	entries, err := historyFromRecords([]historyRecord{r})
+	if err != nil {
+		return repository.HistoryEntry{}, err
+	}
+	return entries[0], nil
+}
+
+func historyToRecords(entries []repository.HistoryEntry) ([]historyRecord, error) {
+	out := make([]historyRecord, 0, len(entries))
+	for _, e := range entries {
+		r := historyRecord{Op: e.Op, At: e.At}
+		for _, c := range e.Changes {
+			cr := changeRecord{BeforeIndex: c.BeforeIndex, AfterIndex: c.AfterIndex}
+			if c.Before != nil {
+				rec, err := recordFromDomain(*c.Before)
+				if err != nil {
+					return nil, fmt.Errorf("encode history: %w", err)
+				}
+				cr.Before = &rec
+			}
+			if c.After != nil {
+				rec, err := recordFromDomain(*c.After)
+				if err != nil {
+					return nil, fmt.Errorf("encode history: %w", err)
+				}
+				cr.After = &rec
+			}
+			r.Changes = append(r.Changes, cr)
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func historyFromRecords(records []historyRecord) ([]repository.HistoryEntry, error) {
+	out := make([]repository.HistoryEntry, 0, len(records))
+	for _, r := range records {
+		e := repository.HistoryEntry{Op: r.Op, At: r.At}
+		for _, cr := range r.Changes {
+			c := repository.TaskChange{BeforeIndex: cr.BeforeIndex, AfterIndex: cr.AfterIndex}
+			if cr.Before != nil {
+				t, err := cr.Before.toDomain()
+				if err != nil {
+					return nil, fmt.Errorf("decode history: %w", err)
+				}
+				c.Before = &t
+			}
+			if cr.After != nil {
+				t, err := cr.After.toDomain()
+				if err != nil {
+					return nil, fmt.Errorf("decode history: %w", err)
+				}
+				c.After = &t
+			}
+			e.Changes = append(e.Changes, c)
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+var _ repository.HistoryRepository = (*TaskStore)(nil)
@@ -0,0 +1,31 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+)
+
+// fileLock is an advisory lock held on a sidecar ".lock" file. The data file
+// itself cannot carry the lock because atomic saves replace it on every write.
+type fileLock struct {
+	f *os.File
+}
+
+// lockPath acquires a shared or exclusive lock for path, blocking until it is
+// available. Other lazytodo processes honour it; other programs may not.
+func lockPath(path string, exclusive bool) (*fileLock, error) {
+	f, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open lock file: %w", err)
+	}
+	if err := lockFD(f, exclusive); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("lock %s: %w", path, err)
+	}
+	return &fileLock{f: f}, nil
+}
+
+func (l *fileLock) unlock() {
+	_ = unlockFD(l.f)
+	_ = l.f.Close()
+}
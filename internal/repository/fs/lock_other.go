@@ -0,0 +1,11 @@
+//go:build !unix && !windows
+
+package fs
+
+import "os"
+
+// Platforms without file locking run unlocked; the on-save change check
+// still catches most concurrent writes.
+func lockFD(f *os.File, exclusive bool) error { return nil }
+
+func unlockFD(f *os.File) error { return nil }
@@ -0,0 +1,47 @@
+//go:build unix
+
+package fs
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLockPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+
+	// shared locks do not exclude each other
+	r1, err := lockPath(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := lockPath(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acquired := make(chan *fileLock)
+	go func() {
+		w, err := lockPath(path, true)
+		if err != nil {
+			t.Error(err)
+		}
+		acquired <- w
+	}()
+	r1.unlock()
+	select {
+	case <-acquired:
+		t.Fatal("exclusive lock taken while a shared lock is held")
+	case <-time.After(50 * time.Millisecond):
+	}
+	r2.unlock()
+	select {
+	case w := <-acquired:
+		if w != nil {
+			w.unlock()
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("exclusive lock not taken after the shared locks were released")
+	}
+}
@@ -0,0 +1,108 @@
+package fs
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+// mergeTasks does a three-way merge of task maps by task ID. base is the
+// state this process last read or wrote, ours is what it wants to write and
+// theirs is what another process has written in the meantime.
+//
+// A side that left a task untouched yields to the side that changed it. When
+// both changed the same task the newer UpdatedAt wins, ours on a tie. A
+// deletion loses against a concurrent edit, so no work is silently dropped.
+func mergeTasks(base, ours, theirs map[domain.TaskStatus][]domain.Task) map[domain.TaskStatus][]domain.Task {
+	baseByID := indexByID(base)
+	oursByID := indexByID(ours)
+	theirsByID := indexByID(theirs)
+
+	resolve := func(id string) (domain.Task, bool) {
+		b, inBase := baseByID[id]
+		o, inOurs := oursByID[id]
+		t, inTheirs := theirsByID[id]
+		switch {
+		case inOurs && inTheirs:
+			if !inBase || reflect.DeepEqual(b, t) {
+				return o, true
+			}
+			if reflect.DeepEqual(b, o) {
+				return t, true
+			}
+			if t.UpdatedAt > o.UpdatedAt {
+				return t, true
+			}
+			return o, true
+		case inOurs:
+			// gone from theirs: deleted there unless it is new here or we edited it
+			if inBase && reflect.DeepEqual(b, o) {
+				return domain.Task{}, false
+			}
+			return o, true
+		case inTheirs:
+			if inBase && reflect.DeepEqual(b, t) {
+				return domain.Task{}, false
+			}
+			return t, true
+		}
+		return domain.Task{}, false
+	}
+
+	out := emptyTaskMap()
+	seen := map[string]bool{}
+	emit := func(id string) {
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		if t, ok := resolve(id); ok {
+			out[t.Status] = append(out[t.Status], t)
+		}
+	}
+	// tasks only the other process knows about go first, mirroring how new
+	// tasks are prepended; everything else keeps our ordering
+	for _, st := range sortedStatuses(theirs) {
+		for _, t := range theirs[st] {
+			if _, ok := oursByID[t.Id]; !ok {
+				emit(t.Id)
+			}
+		}
+	}
+	for _, st := range sortedStatuses(ours) {
+		for _, t := range ours[st] {
+			emit(t.Id)
+		}
+	}
+	return out
+}
+
+func indexByID(m map[domain.TaskStatus][]domain.Task) map[string]domain.Task {
+	out := make(map[string]domain.Task)
+	for _, list := range m {
+		for _, t := range list {
+			out[t.Id] = t
+		}
+	}
+	return out
+}
+
+func sortedStatuses(m map[domain.TaskStatus][]domain.Task) []domain.TaskStatus {
+	out := make([]domain.TaskStatus, 0, len(m))
+	for st := range m {
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+func cloneTaskMap(m map[domain.TaskStatus][]domain.Task) map[domain.TaskStatus][]domain.Task {
+	out := make(map[domain.TaskStatus][]domain.Task, len(m))
+	for k, v := range m {
+		vv := make([]domain.Task, len(v))
+		copy(vv, v)
+		out[k] = vv
+	}
+	return out
+}
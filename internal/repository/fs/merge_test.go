@@ -0,0 +1,147 @@
+package fs
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+func TestMergeTasks(t *testing.T) {
+	task := func(id, content string, status domain.TaskStatus, updated int64) domain.Task {
+		return domain.Task{Id: id, Content: content, Status: status, UpdatedAt: updated}
+	}
+	board := func(tasks ...domain.Task) map[domain.TaskStatus][]domain.Task {
+		m := emptyTaskMap()
+		for _, t := range tasks {
+			m[t.Status] = append(m[t.Status], t)
+		}
+		return m
+	}
+	a := task("a", "a", domain.TaskStatusTodo, 10)
+	b := task("b", "b", domain.TaskStatusTodo, 10)
+	base := board(a, b)
+
+	type column = map[domain.TaskStatus][]string
+	tests := []struct {
+		name         string
+		ours, theirs map[domain.TaskStatus][]domain.Task
+		want         column
+	}{
+		{
+			name:   "disjoint edits",
+			ours:   board(task("a", "a ours", domain.TaskStatusTodo, 20), b),
+			theirs: board(a, task("b", "b theirs", domain.TaskStatusTodo, 20)),
+			want:   column{domain.TaskStatusTodo: {"a ours", "b theirs"}},
+		},
+		{
+			name:   "move against edit of another task",
+			ours:   board(a, task("b", "b ours", domain.TaskStatusTodo, 20)),
+			theirs: board(b, task("a", "a", domain.TaskStatusDone, 20)),
+			want:   column{domain.TaskStatusTodo: {"b ours"}, domain.TaskStatusDone: {"a"}},
+		},
+		{
+			name:   "same task, theirs newer",
+			ours:   board(task("a", "a ours", domain.TaskStatusTodo, 20), b),
+			theirs: board(task("a", "a theirs", domain.TaskStatusTodo, 30), b),
+			want:   column{domain.TaskStatusTodo: {"a theirs", "b"}},
+		},
+		{
+			name:   "same task, ours newer",
+			ours:   board(task("a", "a ours", domain.TaskStatusTodo, 30), b),
+			theirs: board(task("a", "a theirs", domain.TaskStatusTodo, 20), b),
+			want:   column{domain.TaskStatusTodo: {"a ours", "b"}},
+		},
+		{
+			name:   "same task, tie goes to ours",
+			ours:   board(task("a", "a ours", domain.TaskStatusTodo, 20), b),
+			theirs: board(task("a", "a theirs", domain.TaskStatusTodo, 20), b),
+			want:   column{domain.TaskStatusTodo: {"a ours", "b"}},
+		},
+		{
+			name:   "our delete against their edit",
+			ours:   board(b),
+			theirs: board(task("a", "a theirs", domain.TaskStatusTodo, 20), b),
+			want:   column{domain.TaskStatusTodo: {"a theirs", "b"}},
+		},
+		{
+			name:   "their delete against our edit",
+			ours:   board(task("a", "a ours", domain.TaskStatusTodo, 20), b),
+			theirs: board(b),
+			want:   column{domain.TaskStatusTodo: {"a ours", "b"}},
+		},
+		{
+			name:   "delete of an untouched task",
+			ours:   board(a, b),
+			theirs: board(b),
+			want:   column{domain.TaskStatusTodo: {"b"}},
+		},
+		{
+			name:   "both add",
+			ours:   board(task("c", "c ours", domain.TaskStatusTodo, 20), a, b),
+			theirs: board(task("d", "d theirs", domain.TaskStatusTodo, 20), a, b),
+			want:   column{domain.TaskStatusTodo: {"d theirs", "c ours", "a", "b"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeTasks(base, tt.ours, tt.theirs)
+			for st := domain.TaskStatusTodo; st <= domain.TaskStatusTrashed; st++ {
+				var names []string
+				for _, t := range got[st] {
+					names = append(names, t.Content)
+				}
+				if !slices.Equal(names, tt.want[st]) {
+					t.Fatalf("column %d holds %q, want %q", st, names, tt.want[st])
+				}
+			}
+		})
+	}
+}
+
+func TestSaveMergesConcurrentWrites(t *testing.T) {
+	dir := t.TempDir()
+	first, second := NewTaskStore(dir), NewTaskStore(dir)
+	m := emptyTaskMap()
+	m[domain.TaskStatusTodo] = []domain.Task{
+		{Id: "a", Content: "a", Status: domain.TaskStatusTodo, CreatedAt: 1, UpdatedAt: 1},
+		{Id: "b", Content: "b", Status: domain.TaskStatusTodo, CreatedAt: 1, UpdatedAt: 1},
+	}
+	if err := first.Save(m); err != nil {
+		t.Fatal(err)
+	}
+	ours, err := first.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	theirs, err := second.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	theirs[domain.TaskStatusTodo][1].Content = "b theirs"
+	theirs[domain.TaskStatusTodo][1].UpdatedAt = 2
+	if err := second.Save(theirs); err != nil {
+		t.Fatal(err)
+	}
+	ours[domain.TaskStatusTodo][0].Content = "a ours"
+	ours[domain.TaskStatusTodo][0].UpdatedAt = 2
+	if err := first.Save(ours); err != nil {
+		t.Fatal(err)
+	}
+	if _, merged := first.Merged(); !merged {
+		t.Fatal("second save did not merge")
+	}
+
+	got, err := NewTaskStore(dir).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, t := range got[domain.TaskStatusTodo] {
+		names = append(names, t.Content)
+	}
+	if want := []string{"a ours", "b theirs"}; !slices.Equal(names, want) {
+		t.Fatalf("file holds %q, want %q", names, want)
+	}
+}
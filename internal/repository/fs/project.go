@@ -0,0 +1,71 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const (
+	projectDirName  = ".lazytodo"
+	projectFileName = ".lazytodo.json"
+	// projectBackupDirName keeps backups of a .lazytodo.json board next to
+	// it without claiming a generic name like "backups" in the project root.
+	projectBackupDirName = ".lazytodo.backups"
+)
+
+// ProjectBoard is a board that lives inside a working tree, either as a
+// .lazytodo/ directory or as a single .lazytodo.json file.
+type ProjectBoard struct {
+	// Root is the directory holding the board.
+	Root string
+	// Path is the tasks file.
+	Path string
+	// BackupDir is where backups of Path are kept.
+	BackupDir string
+}
+
+// Name is a short label for the board: the name of its root directory.
+func (b ProjectBoard) Name() string { return filepath.Base(b.Root) }
+
+// Store opens the board's task store.
+func (b ProjectBoard) Store() *TaskStore { return NewTaskStoreFile(b.Path, b.BackupDir) }
+
+// FindProjectBoard looks for a project board in start and each of its
+// parents. A .lazytodo/ directory wins over a .lazytodo.json file in the same
+// directory. Directories listed in skip, such as the global data dir, which
+// is also named .lazytodo under $HOME, are never taken for project boards.
+func FindProjectBoard(start string, skip ...string) (ProjectBoard, bool) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return ProjectBoard{}, false
+	}
+	skipped := make(map[string]bool, len(skip))
+	for _, s := range skip {
+		if abs, err := filepath.Abs(s); err == nil {
+			skipped[abs] = true
+		}
+	}
+	for {
+		candidate := filepath.Join(dir, projectDirName)
+		if !skipped[candidate] && dirExists(candidate) {
+			return ProjectBoard{
+				Root:      dir,
+				Path:      filepath.Join(candidate, tasksFileName),
+				BackupDir: filepath.Join(candidate, backupsDirName),
+			}, true
+		}
+		file := filepath.Join(dir, projectFileName)
+		if fi, err := os.Stat(file); err == nil && fi.Mode().IsRegular() {
+			return ProjectBoard{
+				Root:      dir,
+				Path:      file,
+				BackupDir: filepath.Join(dir, projectBackupDirName),
+			}, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ProjectBoard{}, false
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,378 @@
+package fs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+// currentVersion is the tasks file format written by this build.
+//
+//	v1: bare map of status ("0", "1", "2") to tasks with Go field names
+//	v2: {"version": 2, "tasks": [...]} with snake_case fields and named statuses
+//	v3: adds the "trashed" status with deleted_at and trashed_from
+//	v4: adds due_at
+//	v5: adds priority; starred tasks start out as high priority
+//	v6: adds tags
+//	v7: adds rank
+//	v8: adds subtasks
+//	v9: adds notes and status_history
+const currentVersion = 9
+
+// migrations upgrade raw file content from the keyed version to the next
+// one. Every version below currentVersion needs an entry.
+var migrations = map[int]func([]byte) ([]byte, error){
+	1: migrateV1ToV2,
+	2: bumpVersion(3),
+	3: bumpVersion(4),
+	4: migrateV4ToV5,
+	5: bumpVersion(6),
+	6: bumpVersion(7),
+	7: bumpVersion(8),
+	8: bumpVersion(9),
+}
+
+type tasksFile struct {
+	Version int          `json:"version"`
+	Tasks   []taskRecord `json:"tasks"`
+}
+
+// taskRecord is the on-disk shape of a task. It is kept apart from
+// domain.Task so the Go type can change without breaking existing files.
+type taskRecord struct {
+	ID        string          `json:"id"`
+	Content   string          `json:"content"`
+	Notes     string          `json:"notes,omitempty"`
+	Status    string          `json:"status"`
+	Starred   bool            `json:"starred,omitempty"`
+	Priority  string          `json:"priority,omitempty"`
+	Tags      []string        `json:"tags,omitempty"`
+	Subtasks  []subtaskRecord `json:"subtasks,omitempty"`
+	StartedAt int64           `json:"started_at,omitempty"`
+	CreatedAt int64           `json:"created_at,omitempty"`
+	UpdatedAt int64           `json:"updated_at,omitempty"`
+	DueAt     int64           `json:"due_at,omitempty"`
+	Rank      int64           `json:"rank,omitempty"`
+
+	StatusHistory []statusChangeRecord `json:"status_history,omitempty"`
+
+	DeletedAt   int64  `json:"deleted_at,omitempty"`
+	TrashedFrom string `json:"trashed_from,omitempty"`
+}
+
+type subtaskRecord struct {
+	Text string `json:"text"`
+	Done bool   `json:"done,omitempty"`
+}
+
+type statusChangeRecord struct {
+	Status string `json:"status"`
+	At     int64  `json:"at"`
+}
+
+var statusNames = map[domain.TaskStatus]string{
+	domain.TaskStatusTodo:       "todo",
+	domain.TaskStatusInProgress: "in_progress",
+	domain.TaskStatusDone:       "done",
+	domain.TaskStatusTrashed:    "trashed",
+}
+
+// priorityNames maps priorities to their on-disk names; PriorityNone is
+// stored by leaving the field out.
+var priorityNames = map[domain.Priority]string{
+	domain.PriorityLow:    "low",
+	domain.PriorityMedium: "medium",
+	domain.PriorityHigh:   "high",
+	domain.PriorityUrgent: "urgent",
+}
+
+func parsePriorityName(name string) (domain.Priority, error) {
+	if name == "" {
+		return domain.PriorityNone, nil
+	}
+	for p, n := range priorityNames {
+		if n == name {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown priority %q", name)
+}
+
+func statusName(st domain.TaskStatus) (string, error) {
+	name, ok := statusNames[st]
+	if !ok {
+		return "", fmt.Errorf("unknown task status %d", st)
+	}
+	return name, nil
+}
+
+func parseStatusName(name string) (domain.TaskStatus, error) {
+	for st, n := range statusNames {
+		if n == name {
+			return st, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown task status %q", name)
+}
+
+// ErrNewerVersion is returned for files written by a newer lazytodo. Such
+// files are left alone rather than treated as corrupt.
+var ErrNewerVersion = errors.New("tasks file is newer than this version of lazytodo supports")
+
+// fileVersion reports the format version of raw file content. Files without
+// a version field predate versioning and are v1.
+func fileVersion(data []byte) (int, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return 0, err
+	}
+	raw, ok := probe["version"]
+	if !ok {
+		return 1, nil
+	}
+	var v int
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return 0, fmt.Errorf("version: %w", err)
+	}
+	return v, nil
+}
+
+// upgrade runs the migrations needed to bring data to currentVersion and
+// returns the upgraded content along with the version it started at.
+func upgrade(data []byte) ([]byte, int, error) {
+	from, err := fileVersion(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	if from > currentVersion {
+		return nil, from, fmt.Errorf("%w (version %d, supported %d)", ErrNewerVersion, from, currentVersion)
+	}
+	for v := from; v < currentVersion; v++ {
+		migrate, ok := migrations[v]
+		if !ok {
+			return nil, from, fmt.Errorf("no migration from tasks file version %d", v)
+		}
+		if data, err = migrate(data); err != nil {
+			return nil, from, fmt.Errorf("migrate tasks file v%d to v%d: %w", v, v+1, err)
+		}
+	}
+	return data, from, nil
+}
+
+func decodeTasks(data []byte) (map[domain.TaskStatus][]domain.Task, error) {
+	m, _, err := decodeTasksVersion(data)
+	return m, err
+}
+
+// decodeTasksVersion decodes a tasks file of any supported version and also
+// returns the version it was stored in.
+func decodeTasksVersion(data []byte) (map[domain.TaskStatus][]domain.Task, int, error) {
+	data, from, err := upgrade(data)
+	if err != nil {
+		return nil, from, fmt.Errorf("decode tasks: %w", err)
+	}
+	var f tasksFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, from, fmt.Errorf("decode tasks: %w", err)
+	}
+	m := emptyTaskMap()
+	for _, r := range f.Tasks {
+		t, err := r.toDomain()
+		if err != nil {
+			return nil, from, fmt.Errorf("decode tasks: task %s: %w", r.ID, err)
+		}
+		m[t.Status] = append(m[t.Status], t)
+	}
+	return m, from, nil
+}
+
+func encodeTasks(m map[domain.TaskStatus][]domain.Task) ([]byte, error) {
+	f := tasksFile{Version: currentVersion, Tasks: []taskRecord{}}
+	for _, st := range sortedStatuses(m) {
+		for _, t := range m[st] {
+			r, err := recordFromDomain(t)
+			if err != nil {
+				return nil, fmt.Errorf("encode tasks: %w", err)
+			}
+			f.Tasks = append(f.Tasks, r)
+		}
+	}
+	// notes are markdown, where < > and & are common; keep them readable
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(f); err != nil {
+		return nil, fmt.Errorf("encode tasks: %w", err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func recordFromDomain(t domain.Task) (taskRecord, error) {
+	status, err := statusName(t.Status)
+	if err != nil {
+		return taskRecord{}, err
+	}
+	r := taskRecord{
+		ID:        t.Id,
+		Content:   t.Content,
+		Notes:     t.Notes,
+		Status:    status,
+		Starred:   t.IsStarred,
+		Priority:  priorityNames[t.Priority],
+		Tags:      t.Tags,
+		StartedAt: t.StartedAt,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+		DueAt:     t.DueAt,
+		Rank:      t.Rank,
+	}
+	for _, st := range t.Subtasks {
+		r.Subtasks = append(r.Subtasks, subtaskRecord{Text: st.Text, Done: st.Done})
+	}
+	for _, c := range t.StatusHistory {
+		name, err := statusName(c.Status)
+		if err != nil {
+			return taskRecord{}, err
+		}
+		r.StatusHistory = append(r.StatusHistory, statusChangeRecord{Status: name, At: c.At})
+	}
+	if t.Status == domain.TaskStatusTrashed {
+		r.DeletedAt = t.DeletedAt
+		if r.TrashedFrom, err = statusName(t.TrashedFrom); err != nil {
+			return taskRecord{}, err
+		}
+	}
+	return r, nil
+}
+
+func (r taskRecord) toDomain() (domain.Task, error) {
+	status, err := parseStatusName(r.Status)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	t := domain.Task{
+		Id:        r.ID,
+		Content:   r.Content,
+		Notes:     r.Notes,
+		Status:    status,
+		IsStarred: r.Starred,
+		Tags:      r.Tags,
+		StartedAt: r.StartedAt,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+		DueAt:     r.DueAt,
+		Rank:      r.Rank,
+		DeletedAt: r.DeletedAt,
+	}
+	if t.Priority, err = parsePriorityName(r.Priority); err != nil {
+		return domain.Task{}, err
+	}
+	for _, st := range r.Subtasks {
+		t.Subtasks = append(t.Subtasks, domain.Subtask{Text: st.Text, Done: st.Done})
+	}
+	for _, c := range r.StatusHistory {
+		st, err := parseStatusName(c.Status)
+		if err != nil {
+			return domain.Task{}, err
+		}
+		t.StatusHistory = append(t.StatusHistory, domain.StatusChange{Status: st, At: c.At})
+	}
+	if r.TrashedFrom != "" {
+		if t.TrashedFrom, err = parseStatusName(r.TrashedFrom); err != nil {
+			return domain.Task{}, err
+		}
+	}
+	return t, nil
+}
+
+// v1 stored the map[domain.TaskStatus][]domain.Task as encoding/json wrote
+// it. The struct is frozen here so later changes to domain.Task cannot
+// change how old files are read.
+type v1Task struct {
+	Id        string
+	Content   string
+	Status    int
+	IsStarred bool
+	StartedAt int64
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+func migrateV1ToV2(data []byte) ([]byte, error) {
+	var old map[int][]v1Task
+	if err := json.Unmarshal(data, &old); err != nil {
+		return nil, err
+	}
+	keys := make([]int, 0, len(old))
+	for k := range old {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	f := tasksFile{Version: 2, Tasks: []taskRecord{}}
+	for _, k := range keys {
+		// the map key is authoritative; v1 readers never looked at Status
+		status, err := statusName(domain.TaskStatus(k))
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range old[k] {
+			f.Tasks = append(f.Tasks, taskRecord{
+				ID:        t.Id,
+				Content:   t.Content,
+				Status:    status,
+				Starred:   t.IsStarred,
+				StartedAt: t.StartedAt,
+				CreatedAt: t.CreatedAt,
+				UpdatedAt: t.UpdatedAt,
+			})
+		}
+	}
+	return json.Marshal(f)
+}
+
+// bumpVersion returns a migration for versions that only add fields or
+// statuses the previous version cannot contain. The data is unchanged; the
+// version is bumped so that older builds refuse the file instead of dropping
+// fields they do not know on save.
+func bumpVersion(to int) func([]byte) ([]byte, error) {
+	return func(data []byte) ([]byte, error) {
+		var f map[string]json.RawMessage
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, err
+		}
+		f["version"] = json.RawMessage(strconv.Itoa(to))
+		return json.Marshal(f)
+	}
+}
+
+// migrateV4ToV5 gives starred tasks high priority, which is how the star was
+// used before priorities existed. The star itself is kept. Tasks are edited
+// as raw maps so no field is lost to the current taskRecord shape.
+func migrateV4ToV5(data []byte) ([]byte, error) {
+	var f struct {
+		Version int                          `json:"version"`
+		Tasks   []map[string]json.RawMessage `json:"tasks"`
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	for _, t := range f.Tasks {
+		var starred bool
+		if raw, ok := t["starred"]; ok {
+			if err := json.Unmarshal(raw, &starred); err != nil {
+				return nil, fmt.Errorf("starred: %w", err)
+			}
+		}
+		if _, ok := t["priority"]; starred && !ok {
+			t["priority"] = json.RawMessage(`"high"`)
+		}
+	}
+	f.Version = 5
+	return json.Marshal(f)
+}
@@ -0,0 +1,130 @@
+package fs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+func readFixture(t *testing.T, version int) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "schema", fmt.Sprintf("v%d.json", version)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestMigrationsFromFixtures(t *testing.T) {
+	write := domain.Task{Id: "1", Content: "write", Status: domain.TaskStatusTodo, CreatedAt: 100, UpdatedAt: 100}
+	starred := write
+	starred.IsStarred = true
+	starred.Priority = domain.PriorityHigh
+	trashed := domain.Task{Id: "3", Content: "old", Status: domain.TaskStatusTrashed, CreatedAt: 100, UpdatedAt: 300,
+		DeletedAt: 300, TrashedFrom: domain.TaskStatusDone}
+
+	tests := []struct {
+		version int
+		want    []domain.Task
+	}{
+		// the map key wins over the Status field; starred tasks get high priority
+		{1, []domain.Task{starred,
+			{Id: "2", Content: "ship", Status: domain.TaskStatusDone, StartedAt: 150, CreatedAt: 100, UpdatedAt: 200}}},
+		{2, []domain.Task{starred,
+			{Id: "2", Content: "ship", Status: domain.TaskStatusInProgress, StartedAt: 150, CreatedAt: 100, UpdatedAt: 150}}},
+		{3, []domain.Task{starred, trashed}},
+		{4, []domain.Task{func() domain.Task { t := starred; t.DueAt = 86400; return t }(), trashed}},
+		// from v5 on the star no longer implies a priority
+		{5, []domain.Task{func() domain.Task { t := write; t.IsStarred = true; return t }(),
+			{Id: "2", Content: "ship", Status: domain.TaskStatusTodo, Priority: domain.PriorityLow, CreatedAt: 100, UpdatedAt: 100}}},
+		{6, []domain.Task{func() domain.Task {
+			t := write
+			t.Priority, t.Tags = domain.PriorityUrgent, []string{"docs", "release"}
+			return t
+		}()}},
+		{7, []domain.Task{func() domain.Task { t := write; t.Tags, t.Rank = []string{"docs"}, 2048; return t }()}},
+		{8, []domain.Task{func() domain.Task {
+			t := write
+			t.Rank, t.Subtasks = 1024, []domain.Subtask{{Text: "outline", Done: true}, {Text: "draft"}}
+			return t
+		}()}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("v%d", tt.version), func(t *testing.T) {
+			data := readFixture(t, tt.version)
+			if v, err := fileVersion(data); err != nil || v != tt.version {
+				t.Fatalf("fixture version %d (%v), want %d", v, err, tt.version)
+			}
+			// one step
+			next, err := migrations[tt.version](data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v, err := fileVersion(next); err != nil || v != tt.version+1 {
+				t.Fatalf("migrated to version %d (%v), want %d", v, err, tt.version+1)
+			}
+			// all the way to currentVersion
+			m, from, err := decodeTasksVersion(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if from != tt.version {
+				t.Fatalf("decoded from version %d, want %d", from, tt.version)
+			}
+			var got []domain.Task
+			for _, st := range sortedStatuses(m) {
+				got = append(got, m[st]...)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadUpgradesOldFile(t *testing.T) {
+	dir := t.TempDir()
+	old := readFixture(t, 1)
+	if err := os.WriteFile(filepath.Join(dir, tasksFileName), old, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewTaskStore(dir).Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, tasksFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := fileVersion(data); err != nil || v != currentVersion {
+		t.Fatalf("file at version %d (%v), want %d", v, err, currentVersion)
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, backupsDirName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || !strings.HasPrefix(entries[0].Name(), "premigration-v1-") {
+		t.Fatalf("backups %v, want one pre-migration backup", entries)
+	}
+	backup, err := os.ReadFile(filepath.Join(dir, backupsDirName, entries[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(backup, old) {
+		t.Fatalf("backup holds %s, want the original file", backup)
+	}
+}
+
+func TestDecodeNewerVersion(t *testing.T) {
+	_, err := decodeTasks([]byte(fmt.Sprintf(`{"version": %d, "tasks": []}`, currentVersion+1)))
+	if !errors.Is(err, ErrNewerVersion) {
+		t.Fatalf("error %v, want %v", err, ErrNewerVersion)
+	}
+}
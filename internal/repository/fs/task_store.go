@@ -1,94 +1,231 @@
 package fs
 
 import (
-	"encoding/json"
+	"bytes"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/hungtrd/lazytodo/internal/domain"
 	"github.com/hungtrd/lazytodo/internal/repository"
 )
 
-const defaultDirName = ".lazytodo"
 const tasksFileName = "tasks.json"
 
-func defaultDir() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("resolve home dir: %w", err)
-	}
-	return filepath.Join(home, defaultDirName), nil
+// TaskStore keeps all tasks in a single JSON file. Reads and writes hold an
+// advisory lock, and Save merges instead of overwriting when another process
+// changed the file since this store last touched it.
+type TaskStore struct {
+	path       string
+	backupDir  string
+	maxBackups int
+
+	mu sync.Mutex
+	// base is the state last read from or written to disk and hash the
+	// checksum of those bytes; both drive change detection on save
+	base   map[domain.TaskStatus][]domain.Task
+	hash   [sha256.Size]byte
+	merged bool
 }
 
-func tasksFilePath() (string, error) {
-	dir, err := defaultDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(dir, tasksFileName), nil
+// NewTaskStore returns a store for dir/tasks.json. The directory is
+// created on the first save.
+func NewTaskStore(dir string) *TaskStore {
+	return NewTaskStoreFile(filepath.Join(dir, tasksFileName), filepath.Join(dir, backupsDirName))
 }
 
-func ensureDirExists() error {
-	dir, err := defaultDir()
-	if err != nil {
-		return err
-	}
-	return os.MkdirAll(dir, 0o755)
+// NewTaskStoreFile returns a store for a tasks file at an arbitrary path,
+// keeping its backups in backupDir.
+func NewTaskStoreFile(path, backupDir string) *TaskStore {
+	return &TaskStore{path: path, backupDir: backupDir, maxBackups: DefaultMaxBackups}
 }
 
-type TaskStore struct{}
+// SetMaxBackups sets how many timestamped backups are kept under the
+// backups dir. Zero or less disables backups.
+func (s *TaskStore) SetMaxBackups(n int) { s.maxBackups = n }
 
-func NewTaskStore() *TaskStore { return &TaskStore{} }
+// Path returns the location of the tasks file.
+func (s *TaskStore) Path() (string, error) { return s.path, nil }
 
 func emptyTaskMap() map[domain.TaskStatus][]domain.Task {
 	return map[domain.TaskStatus][]domain.Task{
 		domain.TaskStatusTodo:       {},
 		domain.TaskStatusInProgress: {},
 		domain.TaskStatusDone:       {},
+		domain.TaskStatusTrashed:    {},
 	}
 }
 
 func (s *TaskStore) Load() (map[domain.TaskStatus][]domain.Task, error) {
-	path, err := tasksFilePath()
+	path := s.path
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := os.Stat(filepath.Dir(path)); errors.Is(err, fs.ErrNotExist) {
+		s.remember(emptyTaskMap(), nil)
+		return emptyTaskMap(), nil
+	}
+	// exclusive, since a corrupt file gets moved aside below
+	lock, err := lockPath(path, true)
 	if err != nil {
 		return nil, err
 	}
+	defer lock.unlock()
+
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
+			s.remember(emptyTaskMap(), nil)
 			return emptyTaskMap(), nil
 		}
 		return nil, fmt.Errorf("read tasks file: %w", err)
 	}
-	var m map[domain.TaskStatus][]domain.Task
-	if err := json.Unmarshal(data, &m); err != nil {
-		return nil, fmt.Errorf("decode tasks: %w", err)
+	// an empty file, e.g. a freshly touched .lazytodo.json, is an empty board
+	if len(bytes.TrimSpace(data)) == 0 {
+		s.remember(emptyTaskMap(), data)
+		return emptyTaskMap(), nil
 	}
-	if m == nil {
-		m = emptyTaskMap()
+	m, from, err := decodeTasksVersion(data)
+	if err != nil && !errors.Is(err, ErrNewerVersion) {
+		// fall back to the newest backup that still decodes
+		if restored, ok := s.loadNewestBackup(); ok {
+			if qerr := quarantine(path); qerr != nil {
+				return nil, qerr
+			}
+			s.remember(restored, nil)
+			return restored, nil
+		}
+	}
+	if err != nil {
+		return nil, err
 	}
+	if from < currentVersion {
+		if data, err = s.upgradeFile(path, data, from, m); err != nil {
+			return nil, err
+		}
+	}
+	s.remember(m, data)
 	return m, nil
 }
 
+// upgradeFile rewrites an older tasks file in the current format, keeping
+// a copy of the original in the backups dir first. It returns the new content.
+func (s *TaskStore) upgradeFile(path string, old []byte, from int, m map[domain.TaskStatus][]domain.Task) ([]byte, error) {
+	if err := backupBeforeMigration(path, s.backupDir, old, from); err != nil {
+		return nil, err
+	}
+	data, err := encodeTasks(m)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(path, data, 0o644); err != nil {
+		return nil, fmt.Errorf("write migrated tasks file: %w", err)
+	}
+	return data, nil
+}
+
+// remember records m as the last known on-disk state; data is the raw file
+// content it came from, or nil when there is no file.
+func (s *TaskStore) remember(m map[domain.TaskStatus][]domain.Task, data []byte) {
+	s.base = cloneTaskMap(m)
+	if data == nil {
+		s.hash = [sha256.Size]byte{}
+		return
+	}
+	s.hash = sha256.Sum256(data)
+}
+
+func (s *TaskStore) loadNewestBackup() (map[domain.TaskStatus][]domain.Task, bool) {
+	backups, err := listBackups(s.path, s.backupDir)
+	if err != nil {
+		return nil, false
+	}
+	for _, b := range backups {
+		data, err := os.ReadFile(b)
+		if err != nil {
+			continue
+		}
+		if m, err := decodeTasks(data); err == nil {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
 func (s *TaskStore) Save(m map[domain.TaskStatus][]domain.Task) error {
-	if err := ensureDirExists(); err != nil {
+	path := s.path
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create data dir: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lock, err := lockPath(path, true)
+	if err != nil {
 		return err
 	}
-	path, err := tasksFilePath()
+	defer lock.unlock()
+
+	s.merged = false
+	theirs, changed, err := s.changedOnDisk(path)
 	if err != nil {
 		return err
 	}
-	data, err := json.MarshalIndent(m, "", "  ")
+	if changed {
+		m = mergeTasks(s.base, m, theirs)
+		s.merged = true
+	}
+	data, err := encodeTasks(m)
 	if err != nil {
-		return fmt.Errorf("encode tasks: %w", err)
+		return err
+	}
+	if err := createBackup(path, s.backupDir, s.maxBackups); err != nil {
+		return err
 	}
-	if err := os.WriteFile(path, data, 0o644); err != nil {
+	if err := writeFileAtomic(path, data, 0o644); err != nil {
 		return fmt.Errorf("write tasks file: %w", err)
 	}
+	s.remember(m, data)
 	return nil
 }
 
-var _ repository.TaskRepository = (*TaskStore)(nil)
+// changedOnDisk reports whether another process wrote path since the last
+// Load or Save and, if so, returns what it wrote. A missing or undecodable
+// file is not treated as a change: there is nothing to merge with, and the
+// backup taken before writing keeps a copy of whatever was there. A file from
+// a newer lazytodo is an error, so it never gets downgraded.
+func (s *TaskStore) changedOnDisk(path string) (map[domain.TaskStatus][]domain.Task, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, nil
+	}
+	if sha256.Sum256(data) == s.hash {
+		return nil, false, nil
+	}
+	theirs, err := decodeTasks(data)
+	if errors.Is(err, ErrNewerVersion) {
+		return nil, false, err
+	}
+	if err != nil {
+		return nil, false, nil
+	}
+	return theirs, true, nil
+}
+
+// Merged returns the state written by the last Save if that Save had to
+// merge in changes from another process.
+func (s *TaskStore) Merged() (map[domain.TaskStatus][]domain.Task, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.merged {
+		return nil, false
+	}
+	return cloneTaskMap(s.base), true
+}
+
+var (
+	_ repository.TaskRepository        = (*TaskStore)(nil)
+	_ repository.MergingTaskRepository = (*TaskStore)(nil)
+)
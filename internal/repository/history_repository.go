@@ -0,0 +1,32 @@
+package repository
+
+import "github.com/hungtrd/lazytodo/internal/domain"
+
+// TaskChange is one task before and after a mutation. A nil side means the
+// task did not exist; the indexes are its position in its status list.
+type TaskChange struct {
+	Before      *domain.Task
+	After       *domain.Task
+	BeforeIndex int
+	AfterIndex  int
+}
+
+// HistoryEntry is one undoable mutation.
+type HistoryEntry struct {
+	Op      string
+	At      int64
+	Changes []TaskChange
+}
+
+// History is the undo/redo log of a board, oldest entries first.
+type History struct {
+	Undo []HistoryEntry
+	Redo []HistoryEntry
+}
+
+// HistoryRepository is implemented by task repositories that can keep the
+// undo/redo log next to the tasks, so it follows the board it belongs to.
+type HistoryRepository interface {
+	LoadHistory() (History, error)
+	SaveHistory(History) error
+}
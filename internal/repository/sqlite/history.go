@@ -0,0 +1,98 @@
+package sqlite
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hungtrd/lazytodo/internal/repository"
+	"github.com/hungtrd/lazytodo/internal/repository/fs"
+)
+
+const (
+	stackUndo = "undo"
+	stackRedo = "redo"
+)
+
+// LoadHistory reads the undo/redo log. Entries are stored as JSON blobs in
+// the record format of the fs history file; the log is small and only ever
+// read and written as a whole.
+func (s *TaskStore) LoadHistory() (repository.History, error) {
+	rs, err := s.db.Query(`SELECT stack, entry FROM history ORDER BY stack, seq`)
+	if err != nil {
+		return repository.History{}, fmt.Errorf("query history: %w", err)
+	}
+	defer rs.Close()
+	var h repository.History
+	for rs.Next() {
+		var stack, raw string
+		if err := rs.Scan(&stack, &raw); err != nil {
+			return repository.History{}, fmt.Errorf("scan history: %w", err)
+		}
+		e, err := decodeEntry([]byte(raw))
+		if err != nil {
+			return repository.History{}, err
+		}
+		if stack == stackRedo {
+			h.Redo = append(h.Redo, e)
+		} else {
+			h.Undo = append(h.Undo, e)
+		}
+	}
+	if err := rs.Err(); err != nil {
+		return repository.History{}, fmt.Errorf("read history: %w", err)
+	}
+	return h, nil
+}
+
+func (s *TaskStore) SaveHistory(h repository.History) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.Exec(`DELETE FROM history`); err != nil {
+		return fmt.Errorf("clear history: %w", err)
+	}
+	insert := func(stack string, entries []repository.HistoryEntry) error {
+		for i, e := range entries {
+			raw, err := fs.MarshalHistoryEntry(e)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(`INSERT INTO history (stack, seq, entry) VALUES (?, ?, ?)`, stack, i, string(raw)); err != nil {
+				return fmt.Errorf("write history: %w", err)
+			}
+		}
+		return nil
+	}
+	if err := insert(stackUndo, h.Undo); err != nil {
+		return err
+	}
+	if err := insert(stackRedo, h.Redo); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// decodeEntry also reads entries saved before the record format was used,
+// which hold repository.HistoryEntry with its Go field names. They are
+// rewritten in the record format by the next SaveHistory.
+func decodeEntry(raw []byte) (repository.HistoryEntry, error) {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &keys); err != nil {
+		return repository.HistoryEntry{}, fmt.Errorf("decode history: %w", err)
+	}
+	if _, legacy := keys["Op"]; legacy {
+		var e repository.HistoryEntry
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return repository.HistoryEntry{}, fmt.Errorf("decode history: %w", err)
+		}
+		return e, nil
+	}
+	return fs.UnmarshalHistoryEntry(raw)
+}
+
+var _ repository.HistoryRepository = (*TaskStore)(nil)
@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/repository"
+	"github.com/hungtrd/lazytodo/internal/repository/fs"
+)
+
+func openTemp(t *testing.T) *TaskStore {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), DBFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestHistoryUsesRecordFormat(t *testing.T) {
+	s := openTemp(t)
+	before := domain.Task{Id: "a1", Content: "write", Status: domain.TaskStatusTodo, Tags: []string{"docs"}, CreatedAt: 100}
+	after := before
+	after.Status = domain.TaskStatusDone
+	entry := repository.HistoryEntry{Op: "move", At: 200, Changes: []repository.TaskChange{
+		{Before: &before, After: &after, BeforeIndex: 1, AfterIndex: 0},
+	}}
+	if err := s.SaveHistory(repository.History{Undo: []repository.HistoryEntry{entry}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var raw string
+	if err := s.db.QueryRow(`SELECT entry FROM history`).Scan(&raw); err != nil {
+		t.Fatal(err)
+	}
+	want, err := fs.MarshalHistoryEntry(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw != string(want) {
+		t.Fatalf("stored %s, want %s", raw, want)
+	}
+
+	h, err := s.LoadHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Undo) != 1 || len(h.Redo) != 0 || !reflect.DeepEqual(h.Undo[0], entry) {
+		t.Fatalf("loaded %+v, want %+v", h, entry)
+	}
+}
+
+func TestHistoryReadsLegacyEntries(t *testing.T) {
+	s := openTemp(t)
+	task := domain.Task{Id: "a1", Content: "write", Status: domain.TaskStatusTodo, CreatedAt: 100}
+	entry := repository.HistoryEntry{Op: "add", At: 200, Changes: []repository.TaskChange{{After: &task}}}
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.db.Exec(`INSERT INTO history (stack, seq, entry) VALUES (?, 0, ?)`, stackUndo, string(raw)); err != nil {
+		t.Fatal(err)
+	}
+	h, err := s.LoadHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Undo) != 1 || !reflect.DeepEqual(h.Undo[0], entry) {
+		t.Fatalf("loaded %+v, want %+v", h.Undo, entry)
+	}
+}
@@ -0,0 +1,391 @@
+// Package sqlite stores tasks in a SQLite database, one row per task, so a
+// mutation only writes the rows it touched.
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/repository"
+
+	_ "modernc.org/sqlite"
+)
+
+// DBFileName is the database file name inside the data dir.
+const DBFileName = "tasks.db"
+
+// migrations upgrade the schema one step each; PRAGMA user_version records
+// how many have been applied.
+var migrations = []string{
+	`CREATE TABLE tasks (
+	id         TEXT PRIMARY KEY,
+	content    TEXT NOT NULL,
+	status     INTEGER NOT NULL,
+	position   INTEGER NOT NULL,
+	is_starred INTEGER NOT NULL DEFAULT 0,
+	started_at INTEGER NOT NULL DEFAULT 0,
+	created_at INTEGER NOT NULL DEFAULT 0,
+	updated_at INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_tasks_status ON tasks (status, position);
+CREATE INDEX idx_tasks_created_at ON tasks (created_at);
+CREATE TABLE meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);`,
+	`CREATE TABLE history (
+	stack TEXT NOT NULL,
+	seq   INTEGER NOT NULL,
+	entry TEXT NOT NULL,
+	PRIMARY KEY (stack, seq)
+);`,
+	`ALTER TABLE tasks ADD COLUMN deleted_at INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE tasks ADD COLUMN trashed_from INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE tasks ADD COLUMN due_at INTEGER NOT NULL DEFAULT 0;`,
+	// starred tasks start out as high priority (domain.PriorityHigh)
+	`ALTER TABLE tasks ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+UPDATE tasks SET priority = 3 WHERE is_starred = 1;`,
+	// tags are stored space separated; tag names cannot contain spaces
+	`ALTER TABLE tasks ADD COLUMN tags TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE tasks ADD COLUMN rank INTEGER NOT NULL DEFAULT 0;`,
+	// subtasks is a JSON array of {"text", "done"}, empty when there are none
+	`ALTER TABLE tasks ADD COLUMN subtasks TEXT NOT NULL DEFAULT '';`,
+	// status_history is a JSON array of {"status", "at"}
+	`ALTER TABLE tasks ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN status_history TEXT NOT NULL DEFAULT '';`,
+}
+
+// metaJSONMigrated marks a database that already received the one-shot
+// import from another repository.
+const metaJSONMigrated = "json_migrated"
+
+// row is a task as last read from or written to the database.
+type row struct {
+	task     domain.Task
+	position int64
+}
+
+// TaskStore implements repository.TaskRepository on top of SQLite. Save
+// diffs against the state of the last Load/Save and only writes changed
+// rows, which also means rows added by other processes are never deleted.
+type TaskStore struct {
+	path string
+	db   *sql.DB
+
+	mu   sync.Mutex
+	rows map[string]row
+}
+
+// Open opens (creating if needed) the database at path and applies the schema.
+func Open(path string) (*TaskStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create db dir: %w", err)
+	}
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
+	}
+	// a single connection keeps transactions simple and SQLite serialises
+	// writers anyway
+	db.SetMaxOpenConns(1)
+	if err := migrate(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &TaskStore{path: path, db: db, rows: map[string]row{}}, nil
+}
+
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	for ; version < len(migrations); version++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("begin migration: %w", err)
+		}
+		if _, err := tx.Exec(migrations[version]); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migrate schema to v%d: %w", version+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, version+1)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("write schema version: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("commit migration: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close releases the database handle.
+func (s *TaskStore) Close() error { return s.db.Close() }
+
+// Path returns the location of the database file.
+func (s *TaskStore) Path() (string, error) { return s.path, nil }
+
+func (s *TaskStore) Load() (map[domain.TaskStatus][]domain.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs, err := s.db.Query(`SELECT id, content, status, position, is_starred, started_at, created_at, updated_at,
+		deleted_at, trashed_from, due_at, priority, tags, rank, subtasks, notes, status_history
+		FROM tasks ORDER BY status, position`)
+	if err != nil {
+		return nil, fmt.Errorf("query tasks: %w", err)
+	}
+	defer rs.Close()
+
+	m := emptyTaskMap()
+	rows := map[string]row{}
+	for rs.Next() {
+		var r row
+		var starred int
+		var tags, subtasks, history string
+		if err := rs.Scan(&r.task.Id, &r.task.Content, &r.task.Status, &r.position, &starred,
+			&r.task.StartedAt, &r.task.CreatedAt, &r.task.UpdatedAt,
+			&r.task.DeletedAt, &r.task.TrashedFrom, &r.task.DueAt, &r.task.Priority, &tags, &r.task.Rank, &subtasks, &r.task.Notes, &history); err != nil {
+			return nil, fmt.Errorf("scan task: %w", err)
+		}
+		r.task.IsStarred = starred != 0
+		if tags != "" {
+			r.task.Tags = strings.Fields(tags)
+		}
+		if subtasks != "" {
+			var items []subtaskRow
+			if err := json.Unmarshal([]byte(subtasks), &items); err != nil {
+				return nil, fmt.Errorf("decode subtasks of task %s: %w", r.task.Id, err)
+			}
+			for _, it := range items {
+				r.task.Subtasks = append(r.task.Subtasks, domain.Subtask{Text: it.Text, Done: it.Done})
+			}
+		}
+		if history != "" {
+			var changes []statusChangeRow
+			if err := json.Unmarshal([]byte(history), &changes); err != nil {
+				return nil, fmt.Errorf("decode status history of task %s: %w", r.task.Id, err)
+			}
+			for _, c := range changes {
+				r.task.StatusHistory = append(r.task.StatusHistory, domain.StatusChange{Status: c.Status, At: c.At})
+			}
+		}
+		m[r.task.Status] = append(m[r.task.Status], r.task)
+		rows[r.task.Id] = r
+	}
+	if err := rs.Err(); err != nil {
+		return nil, fmt.Errorf("read tasks: %w", err)
+	}
+	s.rows = rows
+	return m, nil
+}
+
+func (s *TaskStore) Save(m map[domain.TaskStatus][]domain.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := make(map[string]row)
+	for st, list := range m {
+		positions := assignPositions(list, st, s.rows)
+		for i, t := range list {
+			next[t.Id] = row{task: t, position: positions[i]}
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	for id := range s.rows {
+		if _, ok := next[id]; ok {
+			continue
+		}
+		if _, err := tx.Exec(`DELETE FROM tasks WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("delete task: %w", err)
+		}
+	}
+	for id, r := range next {
+		if old, ok := s.rows[id]; ok && reflect.DeepEqual(old, r) {
+			continue
+		}
+		if err := upsert(tx, r); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	s.rows = next
+	return nil
+}
+
+// MigrateFrom copies every task from src into the database the first time
+// it is called on an empty database. It reports whether anything was copied.
+func (s *TaskStore) MigrateFrom(src repository.TaskRepository) (bool, error) {
+	var done string
+	err := s.db.QueryRow(`SELECT value FROM meta WHERE key = ?`, metaJSONMigrated).Scan(&done)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("read migration marker: %w", err)
+	}
+	var count int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM tasks`).Scan(&count); err != nil {
+		return false, fmt.Errorf("count tasks: %w", err)
+	}
+	migrated := false
+	if count == 0 {
+		m, err := src.Load()
+		if err != nil {
+			return false, fmt.Errorf("load source tasks: %w", err)
+		}
+		if _, err := s.Load(); err != nil {
+			return false, err
+		}
+		if err := s.Save(m); err != nil {
+			return false, err
+		}
+		migrated = true
+	}
+	if _, err := s.db.Exec(`INSERT INTO meta (key, value) VALUES (?, '1')`, metaJSONMigrated); err != nil {
+		return migrated, fmt.Errorf("write migration marker: %w", err)
+	}
+	return migrated, nil
+}
+
+func upsert(tx *sql.Tx, r row) error {
+	t := r.task
+	starred := 0
+	if t.IsStarred {
+		starred = 1
+	}
+	subtasks := ""
+	if len(t.Subtasks) > 0 {
+		b, err := json.Marshal(subtaskRows(t.Subtasks))
+		if err != nil {
+			return fmt.Errorf("encode subtasks: %w", err)
+		}
+		subtasks = string(b)
+	}
+	history := ""
+	if len(t.StatusHistory) > 0 {
+		rows := make([]statusChangeRow, len(t.StatusHistory))
+		for i, c := range t.StatusHistory {
+			rows[i] = statusChangeRow{Status: c.Status, At: c.At}
+		}
+		b, err := json.Marshal(rows)
+		if err != nil {
+			return fmt.Errorf("encode status history: %w", err)
+		}
+		history = string(b)
+	}
+	_, err := tx.Exec(`INSERT INTO tasks (id, content, status, position, is_starred, started_at, created_at, updated_at,
+			deleted_at, trashed_from, due_at, priority, tags, rank, subtasks, notes, status_history)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			content = excluded.content,
+			status = excluded.status,
+			position = excluded.position,
+			is_starred = excluded.is_starred,
+			started_at = excluded.started_at,
+			created_at = excluded.created_at,
+			updated_at = excluded.updated_at,
+			deleted_at = excluded.deleted_at,
+			trashed_from = excluded.trashed_from,
+			due_at = excluded.due_at,
+			priority = excluded.priority,
+			tags = excluded.tags,
+			rank = excluded.rank,
+			subtasks = excluded.subtasks,
+			notes = excluded.notes,
+			status_history = excluded.status_history`,
+		t.Id, t.Content, t.Status, r.position, starred, t.StartedAt, t.CreatedAt, t.UpdatedAt,
+		t.DeletedAt, t.TrashedFrom, t.DueAt, t.Priority, strings.Join(t.Tags, " "), t.Rank, subtasks, t.Notes, history)
+	if err != nil {
+		return fmt.Errorf("write task: %w", err)
+	}
+	return nil
+}
+
+// assignPositions returns increasing positions for list, reusing the stored
+// position of every task that keeps its place. Tasks prepended to the list,
+// which is how the service adds and moves tasks, get positions below the
+// first kept one so no other row changes. Any other reshuffle renumbers the
+// whole column.
+func assignPositions(list []domain.Task, status domain.TaskStatus, rows map[string]row) []int64 {
+	out := make([]int64, len(list))
+	first := -1
+	for i, t := range list {
+		r, ok := rows[t.Id]
+		if !ok || r.task.Status != status {
+			if first != -1 {
+				return renumber(len(list))
+			}
+			continue
+		}
+		if first != -1 && r.position <= out[i-1] {
+			return renumber(len(list))
+		}
+		if first == -1 {
+			first = i
+		}
+		out[i] = r.position
+	}
+	if first == -1 {
+		return renumber(len(list))
+	}
+	for i := first - 1; i >= 0; i-- {
+		out[i] = out[i+1] - 1
+	}
+	return out
+}
+
+// subtaskRow is the JSON shape of a checklist item in the subtasks column.
+type subtaskRow struct {
+	Text string `json:"text"`
+	Done bool   `json:"done,omitempty"`
+}
+
+// statusChangeRow is the JSON shape of an entry in status_history. Statuses
+// are stored as numbers, as in the status column.
+type statusChangeRow struct {
+	Status domain.TaskStatus `json:"status"`
+	At     int64             `json:"at"`
+}
+
+func subtaskRows(items []domain.Subtask) []subtaskRow {
+	out := make([]subtaskRow, len(items))
+	for i, it := range items {
+		out[i] = subtaskRow{Text: it.Text, Done: it.Done}
+	}
+	return out
+}
+
+func renumber(n int) []int64 {
+	out := make([]int64, n)
+	for i := range out {
+		out[i] = int64(i)
+	}
+	return out
+}
+
+func emptyTaskMap() map[domain.TaskStatus][]domain.Task {
+	return map[domain.TaskStatus][]domain.Task{
+		domain.TaskStatusTodo:       {},
+		domain.TaskStatusInProgress: {},
+		domain.TaskStatusDone:       {},
+		domain.TaskStatusTrashed:    {},
+	}
+}
+
+var _ repository.TaskRepository = (*TaskStore)(nil)
@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+func TestMigrateFromEveryVersion(t *testing.T) {
+	write := domain.Task{Id: "1", Content: "write", Status: domain.TaskStatusTodo, CreatedAt: 100, UpdatedAt: 100}
+	starred := write
+	starred.IsStarred = true
+	starred.Priority = domain.PriorityHigh
+
+	const insert = `INSERT INTO tasks (id, content, status, position, is_starred, created_at, updated_at`
+	tests := []struct {
+		version int
+		insert  string
+		args    []any
+		want    domain.Task
+	}{
+		// starred rows from before priorities get high priority
+		{1, insert + `) VALUES ('1', 'write', ?, 0, 1, 100, 100)`, []any{domain.TaskStatusTodo}, starred},
+		{2, insert + `) VALUES ('1', 'write', ?, 0, 1, 100, 100)`, []any{domain.TaskStatusTodo}, starred},
+		{3, insert + `, deleted_at, trashed_from) VALUES ('3', 'old', ?, 0, 0, 100, 300, 300, ?)`,
+			[]any{domain.TaskStatusTrashed, domain.TaskStatusDone},
+			domain.Task{Id: "3", Content: "old", Status: domain.TaskStatusTrashed, CreatedAt: 100, UpdatedAt: 300,
+				DeletedAt: 300, TrashedFrom: domain.TaskStatusDone}},
+		{4, insert + `, due_at) VALUES ('1', 'write', ?, 0, 1, 100, 100, 86400)`, []any{domain.TaskStatusTodo},
+			func() domain.Task { t := starred; t.DueAt = 86400; return t }()},
+		// from here on the star no longer implies a priority
+		{5, insert + `, priority) VALUES ('1', 'write', ?, 0, 1, 100, 100, ?)`, []any{domain.TaskStatusTodo, domain.PriorityNone},
+			func() domain.Task { t := write; t.IsStarred = true; return t }()},
+		{6, insert + `, tags) VALUES ('1', 'write', ?, 0, 0, 100, 100, 'docs release')`, []any{domain.TaskStatusTodo},
+			func() domain.Task { t := write; t.Tags = []string{"docs", "release"}; return t }()},
+		{7, insert + `, rank) VALUES ('1', 'write', ?, 0, 0, 100, 100, 2048)`, []any{domain.TaskStatusTodo},
+			func() domain.Task { t := write; t.Rank = 2048; return t }()},
+		{8, insert + `, subtasks) VALUES ('1', 'write', ?, 0, 0, 100, 100, '[{"text":"outline","done":true},{"text":"draft"}]')`,
+			[]any{domain.TaskStatusTodo},
+			func() domain.Task {
+				t := write
+				t.Subtasks = []domain.Subtask{{Text: "outline", Done: true}, {Text: "draft"}}
+				return t
+			}()},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("v%d", tt.version), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), DBFileName)
+			db, err := sql.Open("sqlite", path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, m := range migrations[:tt.version] {
+				if _, err := db.Exec(m); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := db.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, tt.version)); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := db.Exec(tt.insert, tt.args...); err != nil {
+				t.Fatal(err)
+			}
+			if err := db.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+			var version int
+			if err := s.db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+				t.Fatal(err)
+			}
+			if version != len(migrations) {
+				t.Fatalf("user_version %d, want %d", version, len(migrations))
+			}
+			m, err := s.Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := m[tt.want.Status]
+			if len(got) != 1 || !reflect.DeepEqual(got[0], tt.want) {
+				t.Fatalf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,3 +8,20 @@ type TaskRepository interface {
 	Load() (map[domain.TaskStatus][]domain.Task, error)
 	Save(map[domain.TaskStatus][]domain.Task) error
 }
+
+// MergingTaskRepository is implemented by repositories that reconcile writes
+// from other processes on Save instead of overwriting them. Merged reports
+// whether the last Save merged and, if so, the state that was written.
+type MergingTaskRepository interface {
+	TaskRepository
+	Merged() (map[domain.TaskStatus][]domain.Task, bool)
+}
+
+// DefaultBoard is the board kept directly in the data directory.
+const DefaultBoard = "default"
+
+// BoardRepository opens the task repository of a named board. Opening a
+// board that has no data yet yields an empty one.
+type BoardRepository interface {
+	Open(name string) (TaskRepository, error)
+}
@@ -0,0 +1,158 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/repository"
+)
+
+var boardNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,31}$`)
+
+// ValidateBoardName checks that name can be used as a board name. Names end
+// up as directory names, so they are restricted to letters, digits, dashes
+// and underscores.
+func ValidateBoardName(name string) error {
+	if !boardNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid board name %q: use up to 32 letters, digits, - or _", name)
+	}
+	return nil
+}
+
+// SetBoards enables named boards. current is the board the task repository
+// passed to NewService belongs to; it is added to the known boards.
+func (s *Service) SetBoards(boards repository.BoardRepository, current string) error {
+	s.boards = boards
+	s.board = current
+	return s.rememberBoard(current, false)
+}
+
+// HasBoards reports whether named boards are available.
+func (s *Service) HasBoards() bool { return s.boards != nil }
+
+// OnBoardSwitch registers fn to be called with the new repository after
+// SwitchBoard changed boards.
+func (s *Service) OnBoardSwitch(fn func(repository.TaskRepository)) { s.onSwitch = fn }
+
+// Boards lists the known boards, DefaultBoard first and the rest by name.
+func (s *Service) Boards() ([]string, error) {
+	cfg, err := s.configRepo.Load()
+	names := map[string]bool{}
+	for _, b := range cfg.Boards {
+		names[b] = true
+	}
+	if s.board != "" {
+		names[s.board] = true
+	}
+	delete(names, repository.DefaultBoard)
+	out := make([]string, 0, len(names)+1)
+	for b := range names {
+		out = append(out, b)
+	}
+	sort.Strings(out)
+	return append([]string{repository.DefaultBoard}, out...), err
+}
+
+// SwitchBoard makes name the active board, creating it if it does not exist
+// yet, and remembers it as the board to open next time.
+func (s *Service) SwitchBoard(name string) (map[domain.TaskStatus][]domain.Task, error) {
+	if s.boards == nil {
+		return nil, errors.New("named boards are not available")
+	}
+	if err := ValidateBoardName(name); err != nil {
+		return nil, err
+	}
+	repo, err := s.boards.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	m, err := repo.Load()
+	if err != nil {
+		return nil, err
+	}
+	s.taskRepo = repo
+	s.tasksByStatus = m
+	s.board = name
+	// history belongs to the board it was recorded on
+	s.history = history{}
+	s.loadHistory()
+	if s.onSwitch != nil {
+		s.onSwitch(repo)
+	}
+	if err := s.rememberBoard(name, true); err != nil {
+		return s.copyState(), err
+	}
+	return s.copyState(), nil
+}
+
+// MoveToBoard moves a task to another board, keeping its ID, status, star
+// and timestamps. The task is written to the target before it is removed
+// here, so a failure never loses it. The task's history stays behind: it
+// is dropped from this board's log instead of being carried over.
+func (s *Service) MoveToBoard(taskID, board string) error {
+	if s.boards == nil {
+		return errors.New("named boards are not available")
+	}
+	if err := ValidateBoardName(board); err != nil {
+		return err
+	}
+	if board == s.board {
+		return nil
+	}
+	status, idx := s.findTask(taskID)
+	if idx == -1 {
+		return errors.New("task not found")
+	}
+	t := s.tasksByStatus[status][idx]
+
+	target, err := s.boards.Open(board)
+	if err != nil {
+		return err
+	}
+	m, err := target.Load()
+	if err != nil {
+		return fmt.Errorf("load board %s: %w", board, err)
+	}
+	t.Rank = topRank(m[status])
+	m[status] = append([]domain.Task{t}, m[status]...)
+	if err := target.Save(m); err != nil {
+		return fmt.Errorf("save board %s: %w", board, err)
+	}
+	if err := s.rememberBoard(board, false); err != nil {
+		return err
+	}
+
+	list := s.tasksByStatus[status]
+	s.tasksByStatus[status] = append(list[:idx], list[idx+1:]...)
+	if err := s.save(); err != nil {
+		return err
+	}
+	s.forget(taskID)
+	return nil
+}
+
+// rememberBoard adds name to the configured boards and, if active is set,
+// makes it the board to open on the next start.
+func (s *Service) rememberBoard(name string, active bool) error {
+	cfg, _ := s.configRepo.Load()
+	known := name == repository.DefaultBoard
+	for _, b := range cfg.Boards {
+		if b == name {
+			known = true
+			break
+		}
+	}
+	if known && (!active || cfg.Board == name) {
+		return nil
+	}
+	if !known {
+		cfg.Boards = append(cfg.Boards, name)
+	}
+	if active {
+		cfg.Board = name
+	}
+	return s.configRepo.Save(cfg)
+}
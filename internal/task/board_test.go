@@ -0,0 +1,63 @@
+package task_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/repository"
+	"github.com/hungtrd/lazytodo/internal/repository/fs"
+	"github.com/hungtrd/lazytodo/internal/task"
+	"github.com/hungtrd/lazytodo/internal/testutil"
+)
+
+type boardDirs string
+
+func (d boardDirs) Open(name string) (repository.TaskRepository, error) {
+	return fs.NewTaskStore(fs.BoardDir(string(d), name)), nil
+}
+
+func TestMoveToBoardLeavesHistoryBehind(t *testing.T) {
+	dir := t.TempDir()
+	svc := testutil.NewService(t, dir)
+	if err := svc.SetBoards(boardDirs(dir), repository.DefaultBoard); err != nil {
+		t.Fatal(err)
+	}
+	moved, err := svc.Add("moved")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Add("stays"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.UpdateContent(moved.Id, "moved away"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.MoveToBoard(moved.Id, "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	// undoing here reverts "stays" and nothing brings "moved" back
+	entry, err := svc.Undo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Op != task.OpAdd || entry.Changes[0].After.Content != "stays" {
+		t.Fatalf("undid %q of %+v, want the add of %q", entry.Op, entry.Changes[0].After, "stays")
+	}
+	if _, err := svc.Undo(); !errors.Is(err, task.ErrNothingToUndo) {
+		t.Fatalf("second undo: %v, want %v", err, task.ErrNothingToUndo)
+	}
+	if got := contents(svc.Tasks()[domain.TaskStatusTodo]); len(got) != 0 {
+		t.Fatalf("default board holds %q, want nothing", got)
+	}
+
+	work, err := svc.SwitchBoard("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := contents(work[domain.TaskStatusTodo]); !slices.Equal(got, []string{"moved away"}) {
+		t.Fatalf("work board holds %q, want the moved task", got)
+	}
+}
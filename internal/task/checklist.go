@@ -0,0 +1,96 @@
+package task
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/repository"
+)
+
+const OpChecklist = "checklist"
+
+var errNoSubtask = errors.New("checklist item not found")
+
+// Progress returns how many checklist items of t are done, and how many
+// there are.
+func Progress(t domain.Task) (done, total int) {
+	for _, st := range t.Subtasks {
+		if st.Done {
+			done++
+		}
+	}
+	return done, len(t.Subtasks)
+}
+
+// AddSubtask appends an item to a task's checklist.
+func (s *Service) AddSubtask(taskID, text string) (domain.Task, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return domain.Task{}, errors.New("checklist item is empty")
+	}
+	return s.editChecklist(taskID, func(items []domain.Subtask) ([]domain.Subtask, error) {
+		return append(items, domain.Subtask{Text: text}), nil
+	})
+}
+
+// ToggleSubtask flips the done flag of checklist item i.
+func (s *Service) ToggleSubtask(taskID string, i int) (domain.Task, error) {
+	return s.editChecklist(taskID, func(items []domain.Subtask) ([]domain.Subtask, error) {
+		if i < 0 || i >= len(items) {
+			return nil, errNoSubtask
+		}
+		items[i].Done = !items[i].Done
+		return items, nil
+	})
+}
+
+// MoveSubtask moves checklist item i to position to.
+func (s *Service) MoveSubtask(taskID string, i, to int) (domain.Task, error) {
+	return s.editChecklist(taskID, func(items []domain.Subtask) ([]domain.Subtask, error) {
+		if i < 0 || i >= len(items) || to < 0 || to >= len(items) {
+			return nil, errNoSubtask
+		}
+		it := items[i]
+		items = append(items[:i], items[i+1:]...)
+		items = append(items[:to], append([]domain.Subtask{it}, items[to:]...)...)
+		return items, nil
+	})
+}
+
+// DeleteSubtask removes checklist item i.
+func (s *Service) DeleteSubtask(taskID string, i int) (domain.Task, error) {
+	return s.editChecklist(taskID, func(items []domain.Subtask) ([]domain.Subtask, error) {
+		if i < 0 || i >= len(items) {
+			return nil, errNoSubtask
+		}
+		return append(items[:i], items[i+1:]...), nil
+	})
+}
+
+// editChecklist applies edit to a copy of a task's checklist, then saves
+// and records the task.
+func (s *Service) editChecklist(taskID string, edit func([]domain.Subtask) ([]domain.Subtask, error)) (domain.Task, error) {
+	status, idx := s.findTask(taskID)
+	if idx == -1 {
+		return domain.Task{}, errors.New("task not found")
+	}
+	before := s.tasksByStatus[status][idx]
+	items, err := edit(append([]domain.Subtask(nil), before.Subtasks...))
+	if err != nil {
+		return domain.Task{}, err
+	}
+	t := before
+	t.Subtasks = nil
+	if len(items) > 0 {
+		t.Subtasks = items
+	}
+	t.UpdatedAt = time.Now().Unix()
+	s.tasksByStatus[status][idx] = t
+	if err := s.save(); err != nil {
+		return domain.Task{}, err
+	}
+	s.record(OpChecklist, repository.TaskChange{Before: &before, After: &t, BeforeIndex: idx, AfterIndex: idx})
+	return t, nil
+}
@@ -0,0 +1,92 @@
+package task_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+	"github.com/hungtrd/lazytodo/internal/testutil"
+)
+
+func TestChecklist(t *testing.T) {
+	svc := testutil.TempService(t)
+	added, err := svc.Add("release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := added.Id
+	steps := []struct {
+		name string
+		edit func() (domain.Task, error)
+		want []domain.Subtask
+	}{
+		{"add", func() (domain.Task, error) { return svc.AddSubtask(id, " tag ") }, []domain.Subtask{{Text: "tag"}}},
+		{"add another", func() (domain.Task, error) { return svc.AddSubtask(id, "announce") },
+			[]domain.Subtask{{Text: "tag"}, {Text: "announce"}}},
+		{"toggle", func() (domain.Task, error) { return svc.ToggleSubtask(id, 0) },
+			[]domain.Subtask{{Text: "tag", Done: true}, {Text: "announce"}}},
+		{"move down", func() (domain.Task, error) { return svc.MoveSubtask(id, 0, 1) },
+			[]domain.Subtask{{Text: "announce"}, {Text: "tag", Done: true}}},
+		{"delete", func() (domain.Task, error) { return svc.DeleteSubtask(id, 0) },
+			[]domain.Subtask{{Text: "tag", Done: true}}},
+		{"delete the last", func() (domain.Task, error) { return svc.DeleteSubtask(id, 0) }, nil},
+	}
+	for _, s := range steps {
+		got, err := s.edit()
+		if err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+		if !reflect.DeepEqual(got.Subtasks, s.want) {
+			t.Fatalf("%s: checklist %+v, want %+v", s.name, got.Subtasks, s.want)
+		}
+	}
+
+	// every step is one undo
+	for i := len(steps) - 2; i >= 0; i-- {
+		e, err := svc.Undo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Op != task.OpChecklist {
+			t.Fatalf("undid %q, want %q", e.Op, task.OpChecklist)
+		}
+		if got := svc.Tasks()[domain.TaskStatusTodo][0].Subtasks; !reflect.DeepEqual(got, steps[i].want) {
+			t.Fatalf("undo to %q: checklist %+v, want %+v", steps[i].name, got, steps[i].want)
+		}
+	}
+}
+
+func TestChecklistErrors(t *testing.T) {
+	svc := testutil.TempService(t)
+	if _, err := svc.AddSubtask("missing", "x"); err == nil {
+		t.Fatal("added to a missing task")
+	}
+	added, err := svc.Add("release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.AddSubtask(added.Id, "  "); err == nil {
+		t.Fatal("added an empty item")
+	}
+	if _, err := svc.AddSubtask(added.Id, "tag"); err != nil {
+		t.Fatal(err)
+	}
+	for name, edit := range map[string]func() (domain.Task, error){
+		"toggle":    func() (domain.Task, error) { return svc.ToggleSubtask(added.Id, 1) },
+		"move from": func() (domain.Task, error) { return svc.MoveSubtask(added.Id, -1, 0) },
+		"move to":   func() (domain.Task, error) { return svc.MoveSubtask(added.Id, 0, 1) },
+		"delete":    func() (domain.Task, error) { return svc.DeleteSubtask(added.Id, 2) },
+	} {
+		if _, err := edit(); err == nil {
+			t.Fatalf("%s out of range succeeded", name)
+		}
+	}
+}
+
+func TestProgress(t *testing.T) {
+	done, total := task.Progress(domain.Task{Subtasks: []domain.Subtask{{Done: true}, {}, {Done: true}}})
+	if done != 2 || total != 3 {
+		t.Fatalf("progress %d/%d, want 2/3", done, total)
+	}
+}
@@ -0,0 +1,110 @@
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// ParseDue turns a due date expression into the start of that day in now's
+// location. Accepted forms:
+//
+//	today, tomorrow (tmr), yesterday
+//	mon .. sun      the next such day after today
+//	+3d, +2w, +1m   days, weeks or months from today
+//	2026-11-01
+//
+// ok is false for "none", "-" or an empty string, which clear a due date.
+func ParseDue(expr string, now time.Time) (due time.Time, ok bool, err error) {
+	expr = strings.ToLower(strings.TrimSpace(expr))
+	today := startOfDay(now)
+	switch expr {
+	case "", "none", "-":
+		return time.Time{}, false, nil
+	case "today", "tod":
+		return today, true, nil
+	case "tomorrow", "tmr", "tom":
+		return today.AddDate(0, 0, 1), true, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true, nil
+	}
+	if wd, found := weekdays[expr]; found {
+		days := (int(wd) - int(today.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, days), true, nil
+	}
+	if strings.HasPrefix(expr, "+") && len(expr) > 2 {
+		n, err := strconv.Atoi(expr[1 : len(expr)-1])
+		if err != nil || n < 0 {
+			return time.Time{}, false, fmt.Errorf("invalid due date %q", expr)
+		}
+		switch expr[len(expr)-1] {
+		case 'd':
+			return today.AddDate(0, 0, n), true, nil
+		case 'w':
+			return today.AddDate(0, 0, 7*n), true, nil
+		case 'm':
+			return today.AddDate(0, n, 0), true, nil
+		}
+		return time.Time{}, false, fmt.Errorf("invalid due date %q: use d, w or m", expr)
+	}
+	if t, err := time.ParseInLocation("2006-01-02", expr, now.Location()); err == nil {
+		return t, true, nil
+	}
+	return time.Time{}, false, fmt.Errorf("invalid due date %q", expr)
+}
+
+// FormatDue renders a due date the way ParseDue reads it back.
+func FormatDue(dueAt int64) string {
+	return time.Unix(dueAt, 0).Format("2006-01-02")
+}
+
+// DueState classifies a due date relative to today.
+type DueState int
+
+const (
+	DueNone DueState = iota
+	DueLater
+	DueThisWeek
+	DueToday
+	DueOverdue
+)
+
+// DueIn reports how a due date relates to now and how many whole days away
+// it is; negative days are overdue.
+func DueIn(dueAt int64, now time.Time) (DueState, int) {
+	if dueAt == 0 {
+		return DueNone, 0
+	}
+	today := startOfDay(now)
+	due := startOfDay(time.Unix(dueAt, 0).In(now.Location()))
+	days := int(due.Sub(today).Round(time.Hour).Hours() / 24)
+	switch {
+	case days < 0:
+		return DueOverdue, days
+	case days == 0:
+		return DueToday, 0
+	case days < 7:
+		return DueThisWeek, days
+	default:
+		return DueLater, days
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
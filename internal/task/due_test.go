@@ -0,0 +1,81 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+func TestParseDue(t *testing.T) {
+	// a Saturday afternoon
+	now := time.Date(2026, 10, 17, 15, 30, 0, 0, time.Local)
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.Local) }
+	tests := []struct {
+		expr    string
+		want    time.Time
+		ok      bool
+		wantErr bool
+	}{
+		{expr: ""},
+		{expr: "  "},
+		{expr: "none"},
+		{expr: "-"},
+		{expr: "today", want: day(10, 17), ok: true},
+		{expr: " Today ", want: day(10, 17), ok: true},
+		{expr: "tmr", want: day(10, 18), ok: true},
+		{expr: "yesterday", want: day(10, 16), ok: true},
+		{expr: "mon", want: day(10, 19), ok: true},
+		{expr: "Friday", want: day(10, 23), ok: true},
+		// the same weekday is next week's, never today
+		{expr: "sat", want: day(10, 24), ok: true},
+		{expr: "+0d", want: day(10, 17), ok: true},
+		{expr: "+3d", want: day(10, 20), ok: true},
+		{expr: "+2w", want: day(10, 31), ok: true},
+		{expr: "+3m", want: time.Date(2027, 1, 17, 0, 0, 0, 0, time.Local), ok: true},
+		{expr: "2026-11-01", want: day(11, 1), ok: true},
+		{expr: "+d", wantErr: true},
+		{expr: "+3y", wantErr: true},
+		{expr: "+-1d", wantErr: true},
+		{expr: "+xd", wantErr: true},
+		{expr: "2026-13-01", wantErr: true},
+		{expr: "11/01/2026", wantErr: true},
+		{expr: "next week", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, ok, err := task.ParseDue(tt.expr, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error %v, want error %v", err, tt.wantErr)
+			}
+			if ok != tt.ok || !got.Equal(tt.want) {
+				t.Fatalf("got %v (ok %v), want %v (ok %v)", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestDueIn(t *testing.T) {
+	now := time.Date(2026, 10, 17, 15, 30, 0, 0, time.Local)
+	day := func(d int) int64 { return time.Date(2026, 10, d, 0, 0, 0, 0, time.Local).Unix() }
+	tests := []struct {
+		name  string
+		due   int64
+		state task.DueState
+		days  int
+	}{
+		{"none", 0, task.DueNone, 0},
+		{"yesterday", day(16), task.DueOverdue, -1},
+		{"today", day(17), task.DueToday, 0},
+		{"tomorrow", day(18), task.DueThisWeek, 1},
+		{"a week out", day(24), task.DueLater, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, days := task.DueIn(tt.due, now)
+			if state != tt.state || days != tt.days {
+				t.Fatalf("got %v, %d days, want %v, %d days", state, days, tt.state, tt.days)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/repository"
+)
+
+// frontMatterFence opens and closes the front matter of an edit file.
+const frontMatterFence = "---"
+
+// TaskEdit holds the editable fields of a task, as read from task text, an
+// edit file or command-line flags.
+type TaskEdit struct {
+	Content  string
+	Tags     []string
+	DueAt    int64
+	Priority domain.Priority
+	Notes    string
+}
+
+// FormatEditFile writes t as an edit file: front matter with the title,
+// tags, due date and priority, followed by the notes.
+//
+//	---
+//	title: Ship the release
+//	tags: ops backend
+//	due: 2026-10-23
+//	priority: high
+//	---
+//
+//	Notes in markdown.
+func FormatEditFile(t domain.Task) string {
+	due := ""
+	if t.DueAt != 0 {
+		due = FormatDue(t.DueAt)
+	}
+	var b strings.Builder
+	b.WriteString(frontMatterFence + "\n")
+	fmt.Fprintf(&b, "title: %s\n", t.Content)
+	fmt.Fprintf(&b, "tags: %s\n", strings.Join(t.Tags, " "))
+	fmt.Fprintf(&b, "due: %s\n", due)
+	fmt.Fprintf(&b, "priority: %s\n", PriorityLabel(t.Priority))
+	b.WriteString("# due takes 2026-10-23, today, fri, +3d or none; priority none, low, medium, high or urgent\n")
+	b.WriteString(frontMatterFence + "\n\n")
+	if t.Notes != "" {
+		b.WriteString(t.Notes + "\n")
+	}
+	return b.String()
+}
+
+// ParseEditFile reads an edit file back. Keys left out or empty clear their
+// field, except the title, which is required. Lines starting with # in the
+// front matter are comments. Errors name the line they are about.
+func ParseEditFile(data string, now time.Time) (TaskEdit, error) {
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != frontMatterFence {
+		return TaskEdit{}, errors.New("line 1: expected --- to open the front matter")
+	}
+	var e TaskEdit
+	seen := map[string]bool{}
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == frontMatterFence {
+			end = i
+			break
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return TaskEdit{}, fmt.Errorf("line %d: expected key: value", i+1)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if seen[key] {
+			return TaskEdit{}, fmt.Errorf("line %d: %s is set twice", i+1, key)
+		}
+		seen[key] = true
+		if err := e.set(key, value, now); err != nil {
+			return TaskEdit{}, fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+	if end == -1 {
+		return TaskEdit{}, errors.New("front matter is not closed with ---")
+	}
+	if e.Content == "" {
+		return TaskEdit{}, errors.New("title is empty")
+	}
+	e.Notes = strings.TrimRight(strings.TrimLeft(strings.Join(lines[end+1:], "\n"), "\n"), " \t\n")
+	return e, nil
+}
+
+func (e *TaskEdit) set(key, value string, now time.Time) error {
+	switch key {
+	case "title":
+		e.Content = strings.Join(strings.Fields(value), " ")
+	case "tags":
+		tags, err := ParseTags(value)
+		if err != nil {
+			return err
+		}
+		e.Tags = tags
+	case "due":
+		due, ok, err := ParseDue(value, now)
+		if err != nil {
+			return err
+		}
+		e.DueAt = 0
+		if ok {
+			e.DueAt = due.Unix()
+		}
+	case "priority":
+		p, err := ParsePriority(value)
+		if err != nil {
+			return err
+		}
+		e.Priority = p
+	default:
+		return fmt.Errorf("unknown key %q: use title, tags, due or priority", key)
+	}
+	return nil
+}
+
+// ApplyEdit replaces the editable fields of a task with e and returns the
+// updated task. Nothing is recorded when e matches the task already.
+func (s *Service) ApplyEdit(taskID string, e TaskEdit) (domain.Task, error) {
+	if strings.TrimSpace(e.Content) == "" {
+		return domain.Task{}, errors.New("content is empty")
+	}
+	status, idx := s.findTask(taskID)
+	if idx == -1 {
+		return domain.Task{}, errors.New("task not found")
+	}
+	before := s.tasksByStatus[status][idx]
+	if before.Content == e.Content && slices.Equal(before.Tags, e.Tags) && before.DueAt == e.DueAt &&
+		before.Priority == e.Priority && before.Notes == e.Notes {
+		return before, nil
+	}
+	t := before
+	t.Content = e.Content
+	t.Tags = e.Tags
+	t.DueAt = e.DueAt
+	t.Priority = e.Priority
+	t.Notes = e.Notes
+	t.UpdatedAt = time.Now().Unix()
+	s.tasksByStatus[status][idx] = t
+	if err := s.save(); err != nil {
+		return domain.Task{}, err
+	}
+	s.record(OpEdit, repository.TaskChange{Before: &before, After: &t, BeforeIndex: idx, AfterIndex: idx})
+	return t, nil
+}
@@ -0,0 +1,80 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+	"github.com/hungtrd/lazytodo/internal/testutil"
+)
+
+func TestEditFileRoundTrip(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local)
+	due := time.Date(2026, 10, 23, 0, 0, 0, 0, time.Local).Unix()
+	tests := []struct {
+		name string
+		task domain.Task
+	}{
+		{"title only", domain.Task{Content: "write docs"}},
+		{"all fields", domain.Task{Content: "ship", Tags: []string{"ops", "release"}, DueAt: due,
+			Priority: domain.PriorityHigh, Notes: "# Plan\n\n- tag\n- announce"}},
+		{"indented code first", domain.Task{Content: "fix", Notes: "    go test ./...\n\nthen push"}},
+		{"nested list first", domain.Task{Content: "fix", Notes: "  - nested\n- top"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := task.ParseEditFile(task.FormatEditFile(tt.task), now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.Content != tt.task.Content || e.Notes != tt.task.Notes || e.DueAt != tt.task.DueAt ||
+				e.Priority != tt.task.Priority || len(e.Tags) != len(tt.task.Tags) {
+				t.Fatalf("got %+v, want the fields of %+v", e, tt.task)
+			}
+			for i := range e.Tags {
+				if e.Tags[i] != tt.task.Tags[i] {
+					t.Fatalf("tags %v, want %v", e.Tags, tt.task.Tags)
+				}
+			}
+		})
+	}
+}
+
+func TestParseEditFileTrimsBlankLines(t *testing.T) {
+	data := "---\ntitle: a\n---\n\n\n  indented\n\n\n"
+	e, err := task.ParseEditFile(data, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Notes != "  indented" {
+		t.Fatalf("notes %q, want %q", e.Notes, "  indented")
+	}
+}
+
+func TestApplyUnchangedEditRecordsNothing(t *testing.T) {
+	svc := testutil.TempService(t)
+	added, err := svc.Add("fix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetNotes(added.Id, "    indented code"); err != nil {
+		t.Fatal(err)
+	}
+	added = svc.Tasks()[domain.TaskStatusTodo][0]
+	e, err := task.ParseEditFile(task.FormatEditFile(added), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ApplyEdit(added.Id, e); err != nil {
+		t.Fatal(err)
+	}
+	// the last entry is still the notes change
+	entry, err := svc.Undo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Op != task.OpNotes {
+		t.Fatalf("last op %q, want %q", entry.Op, task.OpNotes)
+	}
+}
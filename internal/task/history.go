@@ -0,0 +1,219 @@
+package task
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/repository"
+)
+
+// DefaultHistoryLimit is how many mutations can be undone when the config
+// does not set history_limit.
+const DefaultHistoryLimit = 100
+
+const (
+	OpAdd    = "add"
+	OpEdit   = "edit"
+	OpStar   = "star"
+	OpMove   = "move"
+	OpDelete = "delete"
+)
+
+var (
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrNothingToRedo = errors.New("nothing to redo")
+)
+
+// history is the in-memory undo/redo log of the active board.
+type history struct {
+	repository.History
+	loaded bool
+}
+
+// loadHistory reads the persisted log once per board, if persistence is
+// enabled and the repository supports it.
+func (s *Service) loadHistory() {
+	if s.history.loaded {
+		return
+	}
+	s.history = history{loaded: true}
+	hr, ok := s.taskRepo.(repository.HistoryRepository)
+	if !ok {
+		return
+	}
+	if cfg, _ := s.configRepo.Load(); !cfg.PersistHistory {
+		return
+	}
+	if h, err := hr.LoadHistory(); err == nil {
+		s.history.History = h
+	}
+}
+
+func (s *Service) saveHistory() error {
+	hr, ok := s.taskRepo.(repository.HistoryRepository)
+	if !ok {
+		return nil
+	}
+	if cfg, _ := s.configRepo.Load(); !cfg.PersistHistory {
+		return nil
+	}
+	return hr.SaveHistory(s.history.History)
+}
+
+func (s *Service) historyLimit() int {
+	if cfg, _ := s.configRepo.Load(); cfg.HistoryLimit > 0 {
+		return cfg.HistoryLimit
+	}
+	return DefaultHistoryLimit
+}
+
+// record appends a mutation to the undo log and drops the redo log, as
+// usual for editors. Failing to persist the log does not fail the mutation.
+func (s *Service) record(op string, changes ...repository.TaskChange) {
+	s.loadHistory()
+	s.history.Undo = append(s.history.Undo, repository.HistoryEntry{Op: op, At: time.Now().Unix(), Changes: changes})
+	if limit := s.historyLimit(); len(s.history.Undo) > limit {
+		s.history.Undo = append([]repository.HistoryEntry(nil), s.history.Undo[len(s.history.Undo)-limit:]...)
+	}
+	s.history.Redo = nil
+	_ = s.saveHistory()
+}
+
+// forget drops the changes to task id from both logs, and entries left
+// without changes, once the task is no longer on this board; replaying
+// them would bring it back here.
+func (s *Service) forget(id string) {
+	s.loadHistory()
+	prune := func(entries []repository.HistoryEntry) []repository.HistoryEntry {
+		out := entries[:0]
+		for _, e := range entries {
+			var kept []repository.TaskChange
+			for _, c := range e.Changes {
+				if (c.Before == nil || c.Before.Id != id) && (c.After == nil || c.After.Id != id) {
+					kept = append(kept, c)
+				}
+			}
+			if len(kept) > 0 {
+				e.Changes = kept
+				out = append(out, e)
+			}
+		}
+		return out
+	}
+	s.history.Undo = prune(s.history.Undo)
+	s.history.Redo = prune(s.history.Redo)
+	_ = s.saveHistory()
+}
+
+// Undo reverts the most recent mutation and returns it.
+func (s *Service) Undo() (repository.HistoryEntry, error) {
+	s.loadHistory()
+	n := len(s.history.Undo)
+	if n == 0 {
+		return repository.HistoryEntry{}, ErrNothingToUndo
+	}
+	e := s.history.Undo[n-1]
+	s.replay(e.Changes, true)
+	if err := s.save(); err != nil {
+		return repository.HistoryEntry{}, err
+	}
+	s.history.Undo = s.history.Undo[:n-1]
+	s.history.Redo = append(s.history.Redo, e)
+	return e, s.saveHistory()
+}
+
+// Redo re-applies the most recently undone mutation and returns it.
+func (s *Service) Redo() (repository.HistoryEntry, error) {
+	s.loadHistory()
+	n := len(s.history.Redo)
+	if n == 0 {
+		return repository.HistoryEntry{}, ErrNothingToRedo
+	}
+	e := s.history.Redo[n-1]
+	s.replay(e.Changes, false)
+	if err := s.save(); err != nil {
+		return repository.HistoryEntry{}, err
+	}
+	s.history.Redo = s.history.Redo[:n-1]
+	s.history.Undo = append(s.history.Undo, e)
+	return e, s.saveHistory()
+}
+
+// replay applies the changes of an entry, or reverts them with undo set.
+// The indices of an entry are taken against the columns as they were
+// before or after it as a whole, so every task it touches is taken out
+// first and the new versions are put back lowest index first; one change
+// at a time could shuffle tasks that left a column together.
+func (s *Service) replay(changes []repository.TaskChange, undo bool) {
+	type put struct {
+		t     *domain.Task
+		index int
+	}
+	var puts []put
+	for _, c := range changes {
+		from, to, index := c.Before, c.After, c.AfterIndex
+		if undo {
+			from, to, index = c.After, c.Before, c.BeforeIndex
+		}
+		if from != nil {
+			s.applyChange(from, nil, 0)
+		}
+		if to != nil {
+			puts = append(puts, put{to, index})
+		}
+	}
+	sort.SliceStable(puts, func(i, j int) bool { return puts[i].index < puts[j].index })
+	for _, p := range puts {
+		s.applyChange(nil, p.t, p.index)
+	}
+}
+
+// applyChange replaces the task from with to, placing to at index in its
+// status list. Either side may be nil for tasks that are created or removed.
+func (s *Service) applyChange(from, to *domain.Task, index int) {
+	id := ""
+	if from != nil {
+		id = from.Id
+	} else if to != nil {
+		id = to.Id
+	}
+	if status, idx := s.findTask(id); idx != -1 {
+		list := s.tasksByStatus[status]
+		s.tasksByStatus[status] = append(list[:idx], list[idx+1:]...)
+	}
+	if to == nil {
+		return
+	}
+	list := s.tasksByStatus[to.Status]
+	if index < 0 || index > len(list) {
+		index = len(list)
+	}
+	list = append(list, domain.Task{})
+	copy(list[index+1:], list[index:])
+	list[index] = *to
+	s.tasksByStatus[to.Status] = list
+}
+
+// Subject returns the task an entry is about, as it looks after the entry
+// was undone (undone true) or applied.
+func Subject(e repository.HistoryEntry, undone bool) (domain.Task, bool) {
+	if len(e.Changes) == 0 {
+		return domain.Task{}, false
+	}
+	c := e.Changes[0]
+	first, second := c.After, c.Before
+	if undone {
+		first, second = c.Before, c.After
+	}
+	if first != nil {
+		return *first, true
+	}
+	if second != nil {
+		return *second, true
+	}
+	return domain.Task{}, false
+}
+
+func taskPtr(t domain.Task) *domain.Task { return &t }
@@ -0,0 +1,108 @@
+package task_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+	"github.com/hungtrd/lazytodo/internal/testutil"
+)
+
+// TestUndoKeepsOrder checks that undoing and redoing entries that change
+// several tasks at once puts every task back where it was.
+func TestUndoKeepsOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		// act changes the board, which holds a to e in Todo, top to
+		// bottom, with c, d and e also in the trash
+		act func(svc *task.Service, ids map[string]string) error
+	}{
+		{
+			name: "empty trash",
+			act: func(svc *task.Service, _ map[string]string) error {
+				_, err := svc.EmptyTrash()
+				return err
+			},
+		},
+		{
+			name: "import moving tasks that are not next to each other",
+			act: func(svc *task.Service, ids map[string]string) error {
+				var in []domain.Task
+				for _, name := range []string{"a", "f"} {
+					in = append(in, domain.Task{Id: ids[name], Content: name + "!", Status: domain.TaskStatusDone})
+				}
+				_, err := svc.Import(in, task.ImportOptions{})
+				return err
+			},
+		},
+		{
+			name: "reorder",
+			act: func(svc *task.Service, ids map[string]string) error {
+				if err := svc.SetSortMode(task.SortManual); err != nil {
+					return err
+				}
+				return svc.Reorder(ids["f"], 0)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := testutil.TempService(t)
+			ids := map[string]string{}
+			for _, name := range []string{"h", "g", "f", "e", "d", "c", "b", "a"} {
+				added, err := svc.Add(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				ids[name] = added.Id
+			}
+			// the trash holds c, d and e; a, b, f, g and h stay
+			for _, name := range []string{"e", "d", "c"} {
+				if err := svc.Delete(ids[name]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			before := order(svc)
+			if err := tt.act(svc, ids); err != nil {
+				t.Fatal(err)
+			}
+			after := order(svc)
+			if _, err := svc.Undo(); err != nil {
+				t.Fatal(err)
+			}
+			if got := order(svc); !mapsEqual(got, before) {
+				t.Fatalf("after undo %v, want %v", got, before)
+			}
+			if _, err := svc.Redo(); err != nil {
+				t.Fatal(err)
+			}
+			if got := order(svc); !mapsEqual(got, after) {
+				t.Fatalf("after redo %v, want %v", got, after)
+			}
+		})
+	}
+}
+
+// order returns the contents of each column in stored order.
+func order(svc *task.Service) map[domain.TaskStatus][]string {
+	out := map[domain.TaskStatus][]string{}
+	for st, list := range svc.Tasks() {
+		if len(list) > 0 {
+			out[st] = contents(list)
+		}
+	}
+	return out
+}
+
+func mapsEqual(a, b map[domain.TaskStatus][]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if !slices.Equal(v, b[k]) {
+			return false
+		}
+	}
+	return true
+}
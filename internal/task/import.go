@@ -0,0 +1,214 @@
+package task
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/repository"
+)
+
+const OpImport = "import"
+
+// ImportAction says what Import did, or would do, with one task.
+type ImportAction int
+
+const (
+	ImportAdded ImportAction = iota
+	ImportUpdated
+	ImportUnchanged
+)
+
+// ImportResult lists the imported tasks with what happened to each, in the
+// order they were given.
+type ImportResult struct {
+	Tasks   []domain.Task
+	Actions []ImportAction
+}
+
+// ImportOptions tune Import.
+type ImportOptions struct {
+	// DryRun reports what would change without changing anything.
+	DryRun bool
+	// NoNotes and NoChecklist say that the source format cannot hold
+	// notes or a checklist. Tasks it updates keep their own.
+	NoNotes     bool
+	NoChecklist bool
+}
+
+// Count returns how many tasks got action a.
+func (r ImportResult) Count(a ImportAction) int {
+	n := 0
+	for _, got := range r.Actions {
+		if got == a {
+			n++
+		}
+	}
+	return n
+}
+
+// Import brings tasks read from another format onto the board in a single
+// step, which one undo reverts. A task whose Id is on the board updates it:
+// title, notes, star, priority, tags, due date, checklist and status, less
+// the fields opts says the format lacks. Any
+// other task is added at the top of its column with a new ID, keeping its
+// CreatedAt and status history when set. A new task may go straight to the
+// trash, with TrashedFrom set to its column. Tasks on the board that are
+// not in tasks are left alone.
+func (s *Service) Import(tasks []domain.Task, opts ImportOptions) (ImportResult, error) {
+	var res ImportResult
+	now := time.Now().Unix()
+	// tasks that are new to a column go to its top, in the order given
+	top := map[domain.TaskStatus][]domain.Task{}
+	var changes []repository.TaskChange
+	seen := map[string]bool{}
+	for i, in := range tasks {
+		if strings.TrimSpace(in.Content) == "" {
+			return ImportResult{}, fmt.Errorf("task %d: content is empty", i+1)
+		}
+		status, idx := s.findTask(in.Id)
+		if in.Status == domain.TaskStatusTrashed && (in.TrashedFrom == domain.TaskStatusTrashed || idx != -1 && status != domain.TaskStatusTrashed) {
+			return ImportResult{}, fmt.Errorf("task %d: cannot import into the trash", i+1)
+		}
+		if in.Id != "" && seen[in.Id] {
+			return ImportResult{}, fmt.Errorf("task %d: id %s appears twice", i+1, in.Id)
+		}
+		seen[in.Id] = true
+		if in.Id == "" || idx == -1 || status == domain.TaskStatusTrashed {
+			t := in
+			t.Id = newID()
+			// IDs are clock readings, which a fast loop can repeat
+			for seen[t.Id] {
+				t.Id = newID()
+			}
+			seen[t.Id] = true
+			if t.CreatedAt == 0 {
+				t.CreatedAt = now
+			}
+			if t.Status == domain.TaskStatusTrashed {
+				if t.DeletedAt == 0 {
+					t.DeletedAt = now
+				}
+				if len(t.StatusHistory) == 0 {
+					t = enterStatus(t, t.TrashedFrom, t.CreatedAt)
+					t = enterStatus(t, domain.TaskStatusTrashed, t.DeletedAt)
+				}
+			} else {
+				if len(t.StatusHistory) == 0 {
+					t = enterStatus(t, t.Status, t.CreatedAt)
+				}
+				if t.Status == domain.TaskStatusInProgress && t.StartedAt == 0 {
+					t.StartedAt = now
+				}
+				t.DeletedAt, t.TrashedFrom = 0, 0
+			}
+			res.Tasks = append(res.Tasks, t)
+			res.Actions = append(res.Actions, ImportAdded)
+			changes = append(changes, repository.TaskChange{After: taskPtr(t), AfterIndex: len(top[t.Status])})
+			top[t.Status] = append(top[t.Status], t)
+			continue
+		}
+
+		before := s.tasksByStatus[status][idx]
+		if opts.NoNotes {
+			in.Notes = before.Notes
+		}
+		if opts.NoChecklist {
+			in.Subtasks = before.Subtasks
+		}
+		if sameImportFields(before, in) {
+			res.Tasks = append(res.Tasks, before)
+			res.Actions = append(res.Actions, ImportUnchanged)
+			continue
+		}
+		t := before
+		t.Content = in.Content
+		t.Notes = in.Notes
+		t.IsStarred = in.IsStarred
+		t.Priority = in.Priority
+		t.Tags = in.Tags
+		t.DueAt = in.DueAt
+		t.Subtasks = in.Subtasks
+		t.UpdatedAt = now
+		change := repository.TaskChange{Before: taskPtr(before), BeforeIndex: idx, AfterIndex: idx}
+		if in.Status != before.Status {
+			t = enterStatus(t, in.Status, now)
+			if t.Status == domain.TaskStatusInProgress && t.StartedAt == 0 {
+				t.StartedAt = now
+			}
+			change.AfterIndex = len(top[t.Status])
+			top[t.Status] = append(top[t.Status], t)
+		}
+		change.After = taskPtr(t)
+		changes = append(changes, change)
+		res.Tasks = append(res.Tasks, t)
+		res.Actions = append(res.Actions, ImportUpdated)
+	}
+	if opts.DryRun || len(changes) == 0 {
+		return res, nil
+	}
+
+	moved := map[string]bool{}
+	for _, list := range top {
+		for _, t := range list {
+			moved[t.Id] = true
+		}
+	}
+	updated := map[string]domain.Task{}
+	for _, c := range changes {
+		if c.Before != nil {
+			updated[c.After.Id] = *c.After
+		}
+	}
+	for st, list := range s.tasksByStatus {
+		kept := make([]domain.Task, 0, len(list))
+		for _, t := range list {
+			if moved[t.Id] {
+				continue
+			}
+			if u, ok := updated[t.Id]; ok {
+				t = u
+			}
+			kept = append(kept, t)
+		}
+		s.tasksByStatus[st] = kept
+	}
+	for st, list := range top {
+		rest := s.tasksByStatus[st]
+		// rank the new tasks above the rest, in the order given
+		rank := topRank(rest) - int64(len(list)) + 1
+		for i := range list {
+			list[i].Rank = rank + int64(i)
+		}
+		s.tasksByStatus[st] = append(slices.Clone(list), rest...)
+	}
+	for i, c := range changes {
+		if t, ok := findIn(top[c.After.Status], c.After.Id); ok {
+			changes[i].After = taskPtr(t)
+		}
+	}
+	if err := s.save(); err != nil {
+		return ImportResult{}, err
+	}
+	s.record(OpImport, changes...)
+	return res, nil
+}
+
+// sameImportFields reports whether importing in over t would change
+// nothing.
+func sameImportFields(t, in domain.Task) bool {
+	return t.Content == in.Content && t.Notes == in.Notes && t.IsStarred == in.IsStarred &&
+		t.Priority == in.Priority && slices.Equal(t.Tags, in.Tags) && t.DueAt == in.DueAt &&
+		slices.Equal(t.Subtasks, in.Subtasks) && t.Status == in.Status
+}
+
+func findIn(list []domain.Task, id string) (domain.Task, bool) {
+	for _, t := range list {
+		if t.Id == id {
+			return t, true
+		}
+	}
+	return domain.Task{}, false
+}
@@ -0,0 +1,227 @@
+package task_test
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+	"github.com/hungtrd/lazytodo/internal/testutil"
+)
+
+func contents(list []domain.Task) []string {
+	out := make([]string, len(list))
+	for i, t := range list {
+		out[i] = t.Content
+	}
+	return out
+}
+
+func TestImport(t *testing.T) {
+	type column = map[domain.TaskStatus][]string
+	tests := []struct {
+		name string
+		// in builds the tasks to import from the board, which holds
+		// "existing" in Todo with notes and a checklist
+		in      func(existing domain.Task) []domain.Task
+		opts    task.ImportOptions
+		actions []task.ImportAction
+		board   column
+	}{
+		{
+			name: "add",
+			in: func(domain.Task) []domain.Task {
+				return []domain.Task{
+					{Content: "new one", Status: domain.TaskStatusTodo},
+					{Content: "new two", Status: domain.TaskStatusTodo},
+					{Content: "started", Status: domain.TaskStatusInProgress},
+				}
+			},
+			actions: []task.ImportAction{task.ImportAdded, task.ImportAdded, task.ImportAdded},
+			board: column{
+				domain.TaskStatusTodo:       {"new one", "new two", "existing"},
+				domain.TaskStatusInProgress: {"started"},
+			},
+		},
+		{
+			name: "unknown id adds",
+			in: func(domain.Task) []domain.Task {
+				return []domain.Task{{Id: "nope", Content: "other", Status: domain.TaskStatusDone}}
+			},
+			actions: []task.ImportAction{task.ImportAdded},
+			board: column{
+				domain.TaskStatusTodo: {"existing"},
+				domain.TaskStatusDone: {"other"},
+			},
+		},
+		{
+			name: "update",
+			in: func(e domain.Task) []domain.Task {
+				e.Content = "renamed"
+				e.Status = domain.TaskStatusDone
+				e.Notes = ""
+				return []domain.Task{e}
+			},
+			actions: []task.ImportAction{task.ImportUpdated},
+			board:   column{domain.TaskStatusDone: {"renamed"}},
+		},
+		{
+			name:    "unchanged",
+			in:      func(e domain.Task) []domain.Task { return []domain.Task{e} },
+			actions: []task.ImportAction{task.ImportUnchanged},
+			board:   column{domain.TaskStatusTodo: {"existing"}},
+		},
+		{
+			name: "dry run",
+			in: func(e domain.Task) []domain.Task {
+				e.Content = "renamed"
+				return []domain.Task{e, {Content: "new", Status: domain.TaskStatusTodo}}
+			},
+			opts:    task.ImportOptions{DryRun: true},
+			actions: []task.ImportAction{task.ImportUpdated, task.ImportAdded},
+			board:   column{domain.TaskStatusTodo: {"existing"}},
+		},
+		{
+			name: "fields the format lacks are kept",
+			in: func(e domain.Task) []domain.Task {
+				e.Notes, e.Subtasks = "", nil
+				return []domain.Task{e}
+			},
+			opts:    task.ImportOptions{NoNotes: true, NoChecklist: true},
+			actions: []task.ImportAction{task.ImportUnchanged},
+			board:   column{domain.TaskStatusTodo: {"existing"}},
+		},
+		{
+			name: "new task into the trash",
+			in: func(domain.Task) []domain.Task {
+				return []domain.Task{{Content: "archived", Status: domain.TaskStatusTrashed, TrashedFrom: domain.TaskStatusDone}}
+			},
+			actions: []task.ImportAction{task.ImportAdded},
+			board: column{
+				domain.TaskStatusTodo:    {"existing"},
+				domain.TaskStatusTrashed: {"archived"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := testutil.TempService(t)
+			existing, err := svc.Add("existing")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := svc.SetNotes(existing.Id, "keep me"); err != nil {
+				t.Fatal(err)
+			}
+			if existing, err = svc.AddSubtask(existing.Id, "step"); err != nil {
+				t.Fatal(err)
+			}
+			before := svc.Tasks()
+
+			res, err := svc.Import(tt.in(existing), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(res.Actions, tt.actions) {
+				t.Fatalf("actions %v, want %v", res.Actions, tt.actions)
+			}
+			after := svc.Tasks()
+			for st := domain.TaskStatusTodo; st <= domain.TaskStatusTrashed; st++ {
+				if got := contents(after[st]); !slices.Equal(got, tt.board[st]) {
+					t.Fatalf("column %d holds %q, want %q", st, got, tt.board[st])
+				}
+			}
+
+			// one undo reverts the whole import; an import that changed
+			// nothing leaves the history alone
+			entry, err := svc.Undo()
+			if err != nil {
+				t.Fatal(err)
+			}
+			changed := slices.Contains(tt.actions, task.ImportAdded) || slices.Contains(tt.actions, task.ImportUpdated)
+			if changed && !tt.opts.DryRun {
+				if entry.Op != task.OpImport {
+					t.Fatalf("undid %q, want %q", entry.Op, task.OpImport)
+				}
+				if !reflect.DeepEqual(svc.Tasks(), before) {
+					t.Fatalf("undo left %+v, want %+v", svc.Tasks(), before)
+				}
+			} else if entry.Op == task.OpImport {
+				t.Fatal("an import that changed nothing was recorded")
+			}
+		})
+	}
+}
+
+func TestImportAddedFields(t *testing.T) {
+	svc := testutil.TempService(t)
+	res, err := svc.Import([]domain.Task{
+		{Content: "a", Status: domain.TaskStatusInProgress, CreatedAt: 1000},
+		{Content: "b", Status: domain.TaskStatusTrashed, TrashedFrom: domain.TaskStatusDone},
+	}, task.ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := res.Tasks[0], res.Tasks[1]
+	if a.Id == "" || a.Id == b.Id {
+		t.Fatalf("ids %q and %q, want two distinct new ones", a.Id, b.Id)
+	}
+	if a.CreatedAt != 1000 || a.StartedAt == 0 {
+		t.Fatalf("created %d, started %d: want the given creation time and a start time", a.CreatedAt, a.StartedAt)
+	}
+	wantHistory := []domain.TaskStatus{domain.TaskStatusInProgress}
+	if got := historyStatuses(a); !slices.Equal(got, wantHistory) {
+		t.Fatalf("history %v, want %v", got, wantHistory)
+	}
+	if b.DeletedAt == 0 || b.TrashedFrom != domain.TaskStatusDone {
+		t.Fatalf("trashed task: deleted %d from %d", b.DeletedAt, b.TrashedFrom)
+	}
+	wantHistory = []domain.TaskStatus{domain.TaskStatusDone, domain.TaskStatusTrashed}
+	if got := historyStatuses(b); !slices.Equal(got, wantHistory) {
+		t.Fatalf("history %v, want %v", got, wantHistory)
+	}
+	if restored, err := svc.Restore(b.Id); err != nil || restored.Status != domain.TaskStatusDone {
+		t.Fatalf("restore: %v, %v", restored.Status, err)
+	}
+}
+
+func historyStatuses(t domain.Task) []domain.TaskStatus {
+	var out []domain.TaskStatus
+	for _, c := range t.StatusHistory {
+		out = append(out, c.Status)
+	}
+	return out
+}
+
+func TestImportErrors(t *testing.T) {
+	svc := testutil.TempService(t)
+	existing, err := svc.Add("existing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		in   []domain.Task
+		want string
+	}{
+		{"empty content", []domain.Task{{Content: "  "}}, "task 1: content is empty"},
+		{"same id twice", []domain.Task{{Id: existing.Id, Content: "a"}, {Id: existing.Id, Content: "b"}}, "task 2: id"},
+		{"trash an existing task", []domain.Task{{Id: existing.Id, Content: "existing", Status: domain.TaskStatusTrashed}},
+			"cannot import into the trash"},
+		{"trashed from the trash", []domain.Task{{Content: "a", Status: domain.TaskStatusTrashed, TrashedFrom: domain.TaskStatusTrashed}},
+			"cannot import into the trash"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Import(tt.in, task.ImportOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+	if got := contents(svc.Tasks()[domain.TaskStatusTodo]); !slices.Equal(got, []string{"existing"}) {
+		t.Fatalf("failed imports changed the board: %q", got)
+	}
+}
@@ -0,0 +1,33 @@
+package task
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/repository"
+)
+
+const OpNotes = "notes"
+
+// SetNotes replaces a task's notes. Trailing blank lines are dropped.
+func (s *Service) SetNotes(taskID, notes string) error {
+	status, idx := s.findTask(taskID)
+	if idx == -1 {
+		return errors.New("task not found")
+	}
+	notes = strings.TrimRight(notes, " \t\n")
+	before := s.tasksByStatus[status][idx]
+	if before.Notes == notes {
+		return nil
+	}
+	t := before
+	t.Notes = notes
+	t.UpdatedAt = time.Now().Unix()
+	s.tasksByStatus[status][idx] = t
+	if err := s.save(); err != nil {
+		return err
+	}
+	s.record(OpNotes, repository.TaskChange{Before: &before, After: &t, BeforeIndex: idx, AfterIndex: idx})
+	return nil
+}
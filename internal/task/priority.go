@@ -0,0 +1,67 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/repository"
+)
+
+const OpPriority = "priority"
+
+var priorityLabels = []string{
+	domain.PriorityNone:   "none",
+	domain.PriorityLow:    "low",
+	domain.PriorityMedium: "medium",
+	domain.PriorityHigh:   "high",
+	domain.PriorityUrgent: "urgent",
+}
+
+// PriorityLabel returns the display name of p.
+func PriorityLabel(p domain.Priority) string {
+	if p < domain.PriorityNone || p > domain.PriorityUrgent {
+		return "unknown"
+	}
+	return priorityLabels[p]
+}
+
+// ParsePriority reads a priority label as returned by PriorityLabel,
+// ignoring case. An empty name is PriorityNone.
+func ParsePriority(name string) (domain.Priority, error) {
+	if name == "" {
+		return domain.PriorityNone, nil
+	}
+	for p, label := range priorityLabels {
+		if strings.EqualFold(label, name) {
+			return domain.Priority(p), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown priority %q: use none, low, medium, high or urgent", name)
+}
+
+// ShiftPriority raises (delta > 0) or lowers (delta < 0) a task's priority,
+// stopping at none and urgent, and returns the updated task.
+func (s *Service) ShiftPriority(taskID string, delta int) (domain.Task, error) {
+	status, idx := s.findTask(taskID)
+	if idx == -1 {
+		return domain.Task{}, errors.New("task not found")
+	}
+	before := s.tasksByStatus[status][idx]
+	p := before.Priority + domain.Priority(delta)
+	p = max(domain.PriorityNone, min(domain.PriorityUrgent, p))
+	if p == before.Priority {
+		return before, nil
+	}
+	t := before
+	t.Priority = p
+	t.UpdatedAt = time.Now().Unix()
+	s.tasksByStatus[status][idx] = t
+	if err := s.save(); err != nil {
+		return domain.Task{}, err
+	}
+	s.record(OpPriority, repository.TaskChange{Before: &before, After: &t, BeforeIndex: idx, AfterIndex: idx})
+	return t, nil
+}
@@ -0,0 +1,99 @@
+package task_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+	"github.com/hungtrd/lazytodo/internal/testutil"
+)
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    domain.Priority
+		wantErr bool
+	}{
+		{"", domain.PriorityNone, false},
+		{"none", domain.PriorityNone, false},
+		{"Low", domain.PriorityLow, false},
+		{"medium", domain.PriorityMedium, false},
+		{"HIGH", domain.PriorityHigh, false},
+		{"urgent", domain.PriorityUrgent, false},
+		{"critical", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := task.ParsePriority(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Fatalf("ParsePriority(%q) = %v, %v; want %v, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestShiftPriority(t *testing.T) {
+	svc := testutil.TempService(t)
+	added, err := svc.Add("fix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := []struct {
+		delta int
+		want  domain.Priority
+	}{
+		{-1, domain.PriorityNone},
+		{1, domain.PriorityLow},
+		{2, domain.PriorityHigh},
+		{5, domain.PriorityUrgent},
+		{-1, domain.PriorityHigh},
+	}
+	for i, s := range steps {
+		got, err := svc.ShiftPriority(added.Id, s.delta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Priority != s.want {
+			t.Fatalf("step %d: priority %v, want %v", i, got.Priority, s.want)
+		}
+	}
+	// the shift at the bottom changed nothing and was not recorded
+	var ops []string
+	for {
+		e, err := svc.Undo()
+		if err != nil {
+			break
+		}
+		ops = append(ops, e.Op)
+	}
+	want := []string{task.OpPriority, task.OpPriority, task.OpPriority, task.OpPriority, task.OpAdd}
+	if !slices.Equal(ops, want) {
+		t.Fatalf("undid %q, want %q", ops, want)
+	}
+}
+
+func TestSortedOrder(t *testing.T) {
+	list := []domain.Task{
+		{Content: "old", CreatedAt: 1, Rank: 3},
+		{Content: "starred", IsStarred: true, CreatedAt: 2, Rank: 1},
+		{Content: "high", Priority: domain.PriorityHigh, CreatedAt: 3, Rank: 4},
+		{Content: "due later", DueAt: 2000, CreatedAt: 4, Rank: 2},
+		{Content: "due soon", DueAt: 1000, CreatedAt: 5, Rank: 0},
+	}
+	tests := []struct {
+		mode task.SortMode
+		want []string
+	}{
+		{task.SortDefault, []string{"high", "starred", "due soon", "due later", "old"}},
+		{task.SortDue, []string{"due soon", "due later", "high", "starred", "old"}},
+		{task.SortManual, []string{"due soon", "starred", "due later", "old", "high"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, i := range task.SortedOrder(list, tt.mode) {
+			got = append(got, list[i].Content)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Fatalf("%q order %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
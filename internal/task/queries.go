@@ -0,0 +1,57 @@
+package task
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// SavedQuery is a named board filter kept in the config.
+type SavedQuery struct {
+	Name  string
+	Query string
+}
+
+// SavedQueries returns the saved filters sorted by name.
+func (s *Service) SavedQueries() ([]SavedQuery, error) {
+	cfg, err := s.configRepo.Load()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SavedQuery, 0, len(cfg.Queries))
+	for name, q := range cfg.Queries {
+		out = append(out, SavedQuery{Name: name, Query: q})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// SaveQuery stores q under name, replacing a query of the same name. The
+// caller is expected to have checked that q parses.
+func (s *Service) SaveQuery(name, q string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("query name is empty")
+	}
+	cfg, err := s.configRepo.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Queries == nil {
+		cfg.Queries = map[string]string{}
+	}
+	cfg.Queries[name] = strings.TrimSpace(q)
+	return s.configRepo.Save(cfg)
+}
+
+func (s *Service) DeleteQuery(name string) error {
+	cfg, err := s.configRepo.Load()
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Queries[name]; !ok {
+		return errors.New("no saved query " + name)
+	}
+	delete(cfg.Queries, name)
+	return s.configRepo.Save(cfg)
+}
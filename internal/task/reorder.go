@@ -0,0 +1,75 @@
+package task
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/repository"
+)
+
+const OpReorder = "reorder"
+
+// topRank returns a rank that sorts before every task in list, so new and
+// moved tasks land on top in manual order.
+func topRank(list []domain.Task) int64 {
+	if len(list) == 0 {
+		return 0
+	}
+	top := list[0].Rank
+	for _, t := range list[1:] {
+		top = min(top, t.Rank)
+	}
+	return top - 1
+}
+
+// Reorder moves a task to position newPos (0 is the top) of its column in
+// manual order. Positions past either end are clamped. The column is ranked
+// afresh, and only tasks whose rank changed are saved and recorded.
+func (s *Service) Reorder(taskID string, newPos int) error {
+	status, idx := s.findTask(taskID)
+	if idx == -1 {
+		return errors.New("task not found")
+	}
+	list := s.tasksByStatus[status]
+	order := SortedOrder(list, SortManual)
+	from := 0
+	for i, o := range order {
+		if o == idx {
+			from = i
+		}
+	}
+	newPos = max(0, min(newPos, len(order)-1))
+	if newPos == from {
+		return nil
+	}
+	order = append(order[:from], order[from+1:]...)
+	order = append(order[:newPos], append([]int{idx}, order[newPos:]...)...)
+
+	now := time.Now().Unix()
+	var changes []repository.TaskChange
+	for rank, i := range order {
+		before := list[i]
+		if before.Rank == int64(rank) && i != idx {
+			continue
+		}
+		t := before
+		t.Rank = int64(rank)
+		if i == idx {
+			t.UpdatedAt = now
+		}
+		list[i] = t
+		c := repository.TaskChange{Before: &before, After: &t, BeforeIndex: i, AfterIndex: i}
+		if i == idx {
+			// the moved task goes first so undo reports it
+			changes = append([]repository.TaskChange{c}, changes...)
+		} else {
+			changes = append(changes, c)
+		}
+	}
+	if err := s.save(); err != nil {
+		return err
+	}
+	s.record(OpReorder, changes...)
+	return nil
+}
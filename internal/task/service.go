@@ -17,6 +17,13 @@ type Service struct {
 
 	// cached state held in memory while program runs
 	tasksByStatus map[domain.TaskStatus][]domain.Task
+
+	// board names the active board; for project boards it is only a label
+	board    string
+	boards   repository.BoardRepository
+	onSwitch func(repository.TaskRepository)
+
+	history history
 }
 
 func NewService(taskRepo repository.TaskRepository, configRepo repository.ConfigRepository) *Service {
@@ -29,9 +36,22 @@ func (s *Service) Load() (map[domain.TaskStatus][]domain.Task, error) {
 		return nil, err
 	}
 	s.tasksByStatus = m
+	s.loadHistory()
+	if _, err := s.purgeExpired(time.Now()); err != nil {
+		return s.copyState(), err
+	}
 	return s.copyState(), nil
 }
 
+// Tasks returns a copy of the cached tasks.
+func (s *Service) Tasks() map[domain.TaskStatus][]domain.Task { return s.copyState() }
+
+// SetBoardName sets the label shown for the board this service works on.
+func (s *Service) SetBoardName(name string) { s.board = name }
+
+// BoardName returns the label set by SetBoardName.
+func (s *Service) BoardName() string { return s.board }
+
 func (s *Service) GetLayoutVertical() (bool, error) {
 	cfg, err := s.configRepo.Load()
 	if err != nil {
@@ -42,37 +62,86 @@ func (s *Service) GetLayoutVertical() (bool, error) {
 }
 
 func (s *Service) SetLayoutVertical(vertical bool) error {
-	return s.configRepo.Save(repository.Config{Vertical: vertical})
+	// keep the other settings; a broken config file is simply replaced
+	cfg, _ := s.configRepo.Load()
+	cfg.Vertical = vertical
+	return s.configRepo.Save(cfg)
+}
+
+// GetSortMode returns the column order saved in the config.
+func (s *Service) GetSortMode() (SortMode, error) {
+	cfg, err := s.configRepo.Load()
+	if err != nil {
+		return SortDefault, err
+	}
+	return ParseSortMode(cfg.Sort), nil
+}
+
+func (s *Service) SetSortMode(mode SortMode) error {
+	cfg, _ := s.configRepo.Load()
+	cfg.Sort = string(mode)
+	if mode == SortDefault {
+		cfg.Sort = ""
+	}
+	return s.configRepo.Save(cfg)
 }
 
-func (s *Service) Add(content string) (domain.Task, error) {
-	content = strings.TrimSpace(content)
-	if content == "" {
+// Add creates a task from text as typed in the UI; a "due:<date>" word sets
+// the due date (see ParseDue) and "#name" words become tags.
+func (s *Service) Add(text string) (domain.Task, error) {
+	e, err := ParseText(text, time.Now())
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return s.Create(e, domain.TaskStatusTodo)
+}
+
+// Create adds a task with the fields of e at the top of status.
+func (s *Service) Create(e TaskEdit, status domain.TaskStatus) (domain.Task, error) {
+	if strings.TrimSpace(e.Content) == "" {
 		return domain.Task{}, errors.New("content is empty")
 	}
+	if status == domain.TaskStatusTrashed {
+		return domain.Task{}, errors.New("cannot create a task in the trash")
+	}
 	now := time.Now().Unix()
-	t := domain.Task{Id: newID(), Content: content, Status: domain.TaskStatusTodo, CreatedAt: now}
-	s.tasksByStatus[domain.TaskStatusTodo] = append([]domain.Task{t}, s.tasksByStatus[domain.TaskStatusTodo]...)
-	if err := s.taskRepo.Save(s.tasksByStatus); err != nil {
+	t := domain.Task{Id: newID(), Content: e.Content, Notes: e.Notes, Priority: e.Priority, CreatedAt: now, DueAt: e.DueAt, Tags: e.Tags}
+	t.Rank = topRank(s.tasksByStatus[status])
+	t = enterStatus(t, status, now)
+	if status == domain.TaskStatusInProgress {
+		t.StartedAt = now
+	}
+	s.tasksByStatus[status] = append([]domain.Task{t}, s.tasksByStatus[status]...)
+	if err := s.save(); err != nil {
 		return domain.Task{}, err
 	}
+	s.record(OpAdd, repository.TaskChange{After: taskPtr(t)})
 	return t, nil
 }
 
-func (s *Service) UpdateContent(taskID, content string) error {
-	content = strings.TrimSpace(content)
-	if content == "" {
-		return errors.New("content is empty")
+// UpdateContent replaces a task's text as produced by EditText. Attributes
+// missing from the text are cleared.
+func (s *Service) UpdateContent(taskID, text string) error {
+	parsed, err := parseText(text, time.Now())
+	if err != nil {
+		return err
 	}
 	status, idx := s.findTask(taskID)
 	if idx == -1 {
 		return errors.New("task not found")
 	}
-	t := s.tasksByStatus[status][idx]
-	t.Content = content
+	before := s.tasksByStatus[status][idx]
+	t := before
+	t.Content = parsed.content
+	t.DueAt = parsed.dueAt
+	t.Tags = parsed.tags
 	t.UpdatedAt = time.Now().Unix()
 	s.tasksByStatus[status][idx] = t
-	return s.taskRepo.Save(s.tasksByStatus)
+	if err := s.save(); err != nil {
+		return err
+	}
+	s.record(OpEdit, repository.TaskChange{Before: &before, After: &t, BeforeIndex: idx, AfterIndex: idx})
+	return nil
 }
 
 func (s *Service) ToggleStar(taskID string) error {
@@ -80,10 +149,16 @@ func (s *Service) ToggleStar(taskID string) error {
 	if idx == -1 {
 		return errors.New("task not found")
 	}
-	t := s.tasksByStatus[status][idx]
+	before := s.tasksByStatus[status][idx]
+	t := before
 	t.IsStarred = !t.IsStarred
+	t.UpdatedAt = time.Now().Unix()
 	s.tasksByStatus[status][idx] = t
-	return s.taskRepo.Save(s.tasksByStatus)
+	if err := s.save(); err != nil {
+		return err
+	}
+	s.record(OpStar, repository.TaskChange{Before: &before, After: &t, BeforeIndex: idx, AfterIndex: idx})
+	return nil
 }
 
 func (s *Service) Move(taskID string, to domain.TaskStatus) error {
@@ -95,27 +170,76 @@ func (s *Service) Move(taskID string, to domain.TaskStatus) error {
 		return nil
 	}
 	list := s.tasksByStatus[status]
-	t := list[idx]
+	before := list[idx]
+	t := before
 	// remove from source
 	s.tasksByStatus[status] = append(list[:idx], list[idx+1:]...)
 	// insert at top of target
-	t.Status = to
-	t.UpdatedAt = time.Now().Unix()
+	now := time.Now().Unix()
+	t = enterStatus(t, to, now)
+	t.Rank = topRank(s.tasksByStatus[to])
+	t.UpdatedAt = now
+	if to == domain.TaskStatusInProgress && t.StartedAt == 0 {
+		t.StartedAt = now
+	}
 	s.tasksByStatus[to] = append([]domain.Task{t}, s.tasksByStatus[to]...)
-	return s.taskRepo.Save(s.tasksByStatus)
+	if err := s.save(); err != nil {
+		return err
+	}
+	s.record(OpMove, repository.TaskChange{Before: &before, After: &t, BeforeIndex: idx, AfterIndex: 0})
+	return nil
 }
 
+// Delete moves a task to the trash, from where it can be restored until it
+// is purged.
 func (s *Service) Delete(taskID string) error {
 	status, idx := s.findTask(taskID)
 	if idx == -1 {
 		return errors.New("task not found")
 	}
+	if status == domain.TaskStatusTrashed {
+		return errors.New("task is already in the trash")
+	}
 	list := s.tasksByStatus[status]
+	before := list[idx]
+	t := before
 	s.tasksByStatus[status] = append(list[:idx], list[idx+1:]...)
-	return s.taskRepo.Save(s.tasksByStatus)
+	t.TrashedFrom = status
+	t.DeletedAt = time.Now().Unix()
+	t = enterStatus(t, domain.TaskStatusTrashed, t.DeletedAt)
+	s.tasksByStatus[domain.TaskStatusTrashed] = append([]domain.Task{t}, s.tasksByStatus[domain.TaskStatusTrashed]...)
+	if err := s.save(); err != nil {
+		return err
+	}
+	s.record(OpDelete, repository.TaskChange{Before: &before, After: &t, BeforeIndex: idx})
+	return nil
 }
 
 // Helpers
+
+// save persists the cache. When the repository merged in changes made by
+// another process, the cache is replaced with what was actually written.
+func (s *Service) save() error {
+	if err := s.taskRepo.Save(s.tasksByStatus); err != nil {
+		return err
+	}
+	if mr, ok := s.taskRepo.(repository.MergingTaskRepository); ok {
+		if merged, ok := mr.Merged(); ok {
+			s.tasksByStatus = merged
+		}
+	}
+	return nil
+}
+
+// enterStatus sets t's status and appends it to the status history.
+func enterStatus(t domain.Task, status domain.TaskStatus, at int64) domain.Task {
+	t.Status = status
+	history := make([]domain.StatusChange, len(t.StatusHistory), len(t.StatusHistory)+1)
+	copy(history, t.StatusHistory)
+	t.StatusHistory = append(history, domain.StatusChange{Status: status, At: at})
+	return t
+}
+
 func (s *Service) findTask(taskID string) (domain.TaskStatus, int) {
 	for st, list := range s.tasksByStatus {
 		for i := range list {
@@ -137,8 +261,31 @@ func (s *Service) copyState() map[domain.TaskStatus][]domain.Task {
 	return out
 }
 
+// SortMode selects how SortedOrder arranges a column.
+type SortMode string
+
+const (
+	// SortDefault orders by priority, then starred first, then newest.
+	SortDefault SortMode = "default"
+	// SortDue puts the nearest due date first; ties and tasks without one
+	// follow in default order.
+	SortDue SortMode = "due"
+	// SortManual follows the rank set with Reorder.
+	SortManual SortMode = "manual"
+)
+
+// ParseSortMode maps a config value to a SortMode; unknown values fall back
+// to SortDefault.
+func ParseSortMode(s string) SortMode {
+	switch mode := SortMode(s); mode {
+	case SortDue, SortManual:
+		return mode
+	}
+	return SortDefault
+}
+
 // Sorting logic reused by UI for selection mapping
-func SortedOrder(list []domain.Task) []int {
+func SortedOrder(list []domain.Task, mode SortMode) []int {
 	order := make([]int, len(list))
 	for i := range order {
 		order[i] = i
@@ -146,6 +293,18 @@ func SortedOrder(list []domain.Task) []int {
 	sort.SliceStable(order, func(i, j int) bool {
 		ti := list[order[i]]
 		tj := list[order[j]]
+		if mode == SortManual {
+			return ti.Rank < tj.Rank
+		}
+		if mode == SortDue && ti.DueAt != tj.DueAt {
+			if ti.DueAt == 0 || tj.DueAt == 0 {
+				return tj.DueAt == 0
+			}
+			return ti.DueAt < tj.DueAt
+		}
+		if ti.Priority != tj.Priority {
+			return ti.Priority > tj.Priority
+		}
 		if ti.IsStarred != tj.IsStarred {
 			return ti.IsStarred
 		}
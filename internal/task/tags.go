@@ -0,0 +1,60 @@
+package task
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+// TagCount is a tag with the number of tasks carrying it.
+type TagCount struct {
+	Name  string
+	Count int
+}
+
+// BoardTags lists the tags used by tasks on the board, excluding the
+// trash, sorted by name.
+func BoardTags(tasks map[domain.TaskStatus][]domain.Task) []TagCount {
+	counts := map[string]int{}
+	for st, list := range tasks {
+		if st == domain.TaskStatusTrashed {
+			continue
+		}
+		for _, t := range list {
+			for _, tag := range t.Tags {
+				counts[tag]++
+			}
+		}
+	}
+	out := make([]TagCount, 0, len(counts))
+	for name, n := range counts {
+		out = append(out, TagCount{Name: name, Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// HasAnyTag reports whether t carries at least one of tags.
+func HasAnyTag(t domain.Task, tags map[string]bool) bool {
+	for _, tag := range t.Tags {
+		if tags[tag] {
+			return true
+		}
+	}
+	return false
+}
+
+// TagColors returns the configured tag color overrides keyed by lower-case
+// tag name.
+func (s *Service) TagColors() (map[string]string, error) {
+	cfg, err := s.configRepo.Load()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(cfg.TagColors))
+	for name, color := range cfg.TagColors {
+		out[strings.ToLower(strings.TrimPrefix(name, "#"))] = color
+	}
+	return out, nil
+}
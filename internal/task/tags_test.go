@@ -0,0 +1,78 @@
+package task_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+func TestParseTextTags(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		text    string
+		content string
+		tags    []string
+	}{
+		{"write docs #Docs", "write docs", []string{"docs"}},
+		{"#ops deploy #release, #ops", "deploy", []string{"ops", "release"}},
+		{"fix #123 and #4-beta/2", "fix #123 and", []string{"4-beta/2"}},
+		{"a # b #no*tag", "a # b #no*tag", nil},
+		{"ship due:tmr #go", "ship", []string{"go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			e, err := task.ParseText(tt.text, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.Content != tt.content || !slices.Equal(e.Tags, tt.tags) {
+				t.Fatalf("got %q with %q, want %q with %q", e.Content, e.Tags, tt.content, tt.tags)
+			}
+			back, err := task.ParseText(task.EditText(domain.Task{Content: e.Content, Tags: e.Tags, DueAt: e.DueAt}), now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if back.Content != e.Content || !slices.Equal(back.Tags, e.Tags) || back.DueAt != e.DueAt {
+				t.Fatalf("EditText round trip gave %+v, want %+v", back, e)
+			}
+		})
+	}
+	if _, err := task.ParseText("#only #tags", now); err == nil {
+		t.Fatal("text without content parsed")
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    []string
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"docs, #Ops  release", []string{"docs", "ops", "release"}, false},
+		{"a,a,#A", []string{"a"}, false},
+		{"docs, 42", nil, true},
+		{"bad*tag", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := task.ParseTags(tt.list)
+		if (err != nil) != tt.wantErr || !slices.Equal(got, tt.want) {
+			t.Fatalf("ParseTags(%q) = %q, %v; want %q, error %v", tt.list, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestBoardTags(t *testing.T) {
+	tasks := map[domain.TaskStatus][]domain.Task{
+		domain.TaskStatusTodo:    {{Tags: []string{"ops", "docs"}}, {Tags: []string{"ops"}}},
+		domain.TaskStatusDone:    {{Tags: []string{"docs"}}},
+		domain.TaskStatusTrashed: {{Tags: []string{"gone", "ops"}}},
+	}
+	want := []task.TagCount{{Name: "docs", Count: 2}, {Name: "ops", Count: 2}}
+	if got := task.BoardTags(tasks); !slices.Equal(got, want) {
+		t.Fatalf("tags %+v, want %+v", got, want)
+	}
+}
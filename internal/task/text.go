@@ -0,0 +1,124 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+// dueToken prefixes the due date in task text, e.g. "call bob due:fri".
+const dueToken = "due:"
+
+// taskText is task input split into the content and the attributes written
+// inline with it.
+type taskText struct {
+	content string
+	dueAt   int64
+	tags    []string
+}
+
+// parseText pulls the inline attributes out of text typed for a task. The
+// remaining words become the content.
+func parseText(text string, now time.Time) (taskText, error) {
+	var out taskText
+	words := make([]string, 0, 8)
+	for _, w := range strings.Fields(text) {
+		if len(w) > len(dueToken) && strings.EqualFold(w[:len(dueToken)], dueToken) {
+			due, ok, err := ParseDue(w[len(dueToken):], now)
+			if err != nil {
+				return taskText{}, err
+			}
+			out.dueAt = 0
+			if ok {
+				out.dueAt = due.Unix()
+			}
+			continue
+		}
+		if tag, ok := parseTag(w); ok {
+			out.tags = addTag(out.tags, tag)
+			continue
+		}
+		words = append(words, w)
+	}
+	out.content = strings.Join(words, " ")
+	if out.content == "" {
+		return taskText{}, errors.New("content is empty")
+	}
+	return out, nil
+}
+
+// ParseText reads task text as typed in the UI, with "due:<date>" and
+// "#tag" words, into the fields it sets.
+func ParseText(text string, now time.Time) (TaskEdit, error) {
+	parsed, err := parseText(text, now)
+	if err != nil {
+		return TaskEdit{}, err
+	}
+	return TaskEdit{Content: parsed.content, Tags: parsed.tags, DueAt: parsed.dueAt}, nil
+}
+
+// EditText returns the text to prefill when editing t, so that saving it
+// unchanged keeps every attribute.
+func EditText(t domain.Task) string {
+	text := t.Content
+	for _, tag := range t.Tags {
+		text += " #" + tag
+	}
+	if t.DueAt != 0 {
+		text += " " + dueToken + FormatDue(t.DueAt)
+	}
+	return text
+}
+
+// parseTag reads a "#tag" word. Trailing punctuation is dropped, and
+// all-digit words such as "#123" are left alone since they usually refer to
+// issues.
+func parseTag(word string) (string, bool) {
+	if !strings.HasPrefix(word, "#") {
+		return "", false
+	}
+	name := strings.TrimRight(word[1:], ".,;:!?")
+	if name == "" {
+		return "", false
+	}
+	digits := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_/.", r) {
+			return "", false
+		}
+		if !unicode.IsDigit(r) {
+			digits = false
+		}
+	}
+	if digits {
+		return "", false
+	}
+	return strings.ToLower(name), true
+}
+
+// ParseTags reads a list of tag names separated by commas or spaces; the #
+// in front of each is optional.
+func ParseTags(list string) ([]string, error) {
+	var tags []string
+	for _, w := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		tag, ok := parseTag("#" + strings.TrimPrefix(w, "#"))
+		if !ok {
+			return nil, fmt.Errorf("invalid tag %q", w)
+		}
+		tags = addTag(tags, tag)
+	}
+	return tags, nil
+}
+
+func addTag(tags []string, tag string) []string {
+	for _, t := range tags {
+		if t == tag {
+			return tags
+		}
+	}
+	return append(tags, tag)
+}
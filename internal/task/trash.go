@@ -0,0 +1,118 @@
+package task
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/repository"
+)
+
+// DefaultTrashRetention is how long deleted tasks are kept when the config
+// does not set trash_retention_days.
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+const (
+	OpRestore = "restore"
+	OpPurge   = "purge"
+)
+
+// Trash returns the deleted tasks, most recently deleted first.
+func (s *Service) Trash() []domain.Task {
+	out := append([]domain.Task(nil), s.tasksByStatus[domain.TaskStatusTrashed]...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].DeletedAt > out[j].DeletedAt })
+	return out
+}
+
+// Restore moves a task out of the trash, back to the column it was deleted
+// from.
+func (s *Service) Restore(taskID string) (domain.Task, error) {
+	idx := s.trashIndex(taskID)
+	if idx == -1 {
+		return domain.Task{}, errors.New("task not found in trash")
+	}
+	list := s.tasksByStatus[domain.TaskStatusTrashed]
+	before := list[idx]
+	t := before
+	s.tasksByStatus[domain.TaskStatusTrashed] = append(list[:idx], list[idx+1:]...)
+	t.UpdatedAt = time.Now().Unix()
+	t = enterStatus(t, t.TrashedFrom, t.UpdatedAt)
+	t.TrashedFrom = domain.TaskStatusTodo
+	t.DeletedAt = 0
+	s.tasksByStatus[t.Status] = append([]domain.Task{t}, s.tasksByStatus[t.Status]...)
+	if err := s.save(); err != nil {
+		return domain.Task{}, err
+	}
+	s.record(OpRestore, repository.TaskChange{Before: &before, After: &t, BeforeIndex: idx})
+	return t, nil
+}
+
+// Purge deletes a trashed task for good. Until the history moves past it,
+// a purge can still be undone.
+func (s *Service) Purge(taskID string) error {
+	idx := s.trashIndex(taskID)
+	if idx == -1 {
+		return errors.New("task not found in trash")
+	}
+	list := s.tasksByStatus[domain.TaskStatusTrashed]
+	before := list[idx]
+	s.tasksByStatus[domain.TaskStatusTrashed] = append(list[:idx], list[idx+1:]...)
+	if err := s.save(); err != nil {
+		return err
+	}
+	s.record(OpPurge, repository.TaskChange{Before: &before, BeforeIndex: idx})
+	return nil
+}
+
+// EmptyTrash purges every trashed task and returns how many there were.
+func (s *Service) EmptyTrash() (int, error) {
+	list := s.tasksByStatus[domain.TaskStatusTrashed]
+	if len(list) == 0 {
+		return 0, nil
+	}
+	changes := make([]repository.TaskChange, len(list))
+	for i := range list {
+		changes[i] = repository.TaskChange{Before: taskPtr(list[i]), BeforeIndex: i}
+	}
+	s.tasksByStatus[domain.TaskStatusTrashed] = []domain.Task{}
+	if err := s.save(); err != nil {
+		return 0, err
+	}
+	s.record(OpPurge, changes...)
+	return len(list), nil
+}
+
+// purgeExpired drops trashed tasks older than the retention period. It is
+// housekeeping rather than a user action, so it is not recorded in history.
+func (s *Service) purgeExpired(now time.Time) (int, error) {
+	retention := DefaultTrashRetention
+	if cfg, _ := s.configRepo.Load(); cfg.TrashRetentionDays < 0 {
+		return 0, nil
+	} else if cfg.TrashRetentionDays > 0 {
+		retention = time.Duration(cfg.TrashRetentionDays) * 24 * time.Hour
+	}
+	cutoff := now.Add(-retention).Unix()
+	list := s.tasksByStatus[domain.TaskStatusTrashed]
+	kept := make([]domain.Task, 0, len(list))
+	for _, t := range list {
+		if t.DeletedAt > cutoff {
+			kept = append(kept, t)
+		}
+	}
+	purged := len(list) - len(kept)
+	if purged == 0 {
+		return 0, nil
+	}
+	s.tasksByStatus[domain.TaskStatusTrashed] = kept
+	return purged, s.save()
+}
+
+func (s *Service) trashIndex(taskID string) int {
+	for i, t := range s.tasksByStatus[domain.TaskStatusTrashed] {
+		if t.Id == taskID {
+			return i
+		}
+	}
+	return -1
+}
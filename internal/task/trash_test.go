@@ -0,0 +1,58 @@
+package task
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/repository"
+	"github.com/hungtrd/lazytodo/internal/repository/fs"
+)
+
+func TestPurgeExpired(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local)
+	daysAgo := func(d int) int64 { return now.AddDate(0, 0, -d).Unix() }
+	tests := []struct {
+		name      string
+		retention int
+		kept      []string
+	}{
+		{"disabled", -1, []string{"1 day", "10 days", "40 days"}},
+		{"default of 30 days", 0, []string{"1 day", "10 days"}},
+		{"7 days", 7, []string{"1 day"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			cfg := fs.NewConfigStore(dir)
+			if err := cfg.Save(repository.Config{TrashRetentionDays: tt.retention}); err != nil {
+				t.Fatal(err)
+			}
+			svc := NewService(fs.NewTaskStore(dir), cfg)
+			if _, err := svc.Load(); err != nil {
+				t.Fatal(err)
+			}
+			svc.tasksByStatus[domain.TaskStatusTrashed] = []domain.Task{
+				{Id: "a", Content: "1 day", Status: domain.TaskStatusTrashed, DeletedAt: daysAgo(1)},
+				{Id: "b", Content: "10 days", Status: domain.TaskStatusTrashed, DeletedAt: daysAgo(10)},
+				{Id: "c", Content: "40 days", Status: domain.TaskStatusTrashed, DeletedAt: daysAgo(40)},
+			}
+
+			purged, err := svc.purgeExpired(now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := 3 - len(tt.kept); purged != want {
+				t.Fatalf("purged %d, want %d", purged, want)
+			}
+			var kept []string
+			for _, task := range svc.tasksByStatus[domain.TaskStatusTrashed] {
+				kept = append(kept, task.Content)
+			}
+			if !slices.Equal(kept, tt.kept) {
+				t.Fatalf("trash holds %q, want %q", kept, tt.kept)
+			}
+		})
+	}
+}
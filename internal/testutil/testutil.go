@@ -0,0 +1,25 @@
+// Package testutil holds helpers shared by the tests of several packages.
+package testutil
+
+import (
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/repository/fs"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+// NewService returns a loaded service that keeps its files in dir.
+func NewService(t testing.TB, dir string) *task.Service {
+	t.Helper()
+	svc := task.NewService(fs.NewTaskStore(dir), fs.NewConfigStore(dir))
+	if _, err := svc.Load(); err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+// TempService returns a loaded service in a new temporary directory.
+func TempService(t testing.TB) *task.Service {
+	t.Helper()
+	return NewService(t, t.TempDir())
+}
@@ -5,8 +5,10 @@ import (
 	"github.com/hungtrd/lazytodo/internal/task"
 )
 
-func Run(svc *task.Service) error {
-	p := tea.NewProgram(InitialModel(svc), tea.WithAltScreen())
+// Run starts the TUI. changes may be nil; otherwise every value received on
+// it reloads the board from storage.
+func Run(svc *task.Service, changes <-chan struct{}) error {
+	p := tea.NewProgram(InitialModel(svc, changes), tea.WithAltScreen())
 	_, err := p.Run()
 	return err
 }
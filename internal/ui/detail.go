@@ -0,0 +1,339 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+// detailView is the side pane showing the selected task. It follows the
+// board selection; modeDetail means the pane has the keyboard.
+type detailView struct {
+	// taskID is the task cursor belongs to
+	taskID string
+	cursor int
+	// adding is set while a new checklist item is typed into input
+	adding bool
+	input  textinput.Model
+	// editing is set while the notes are edited in notes
+	editing bool
+	notes   textarea.Model
+}
+
+// minPaneBoardWidth is the narrowest board kept beside the pane; below it
+// the pane takes the whole width.
+const minPaneBoardWidth = 48
+
+func newDetailView() *detailView {
+	ti := textinput.New()
+	ti.Placeholder = "checklist item..."
+	ti.Prompt = "➤ "
+	ti.CharLimit = 256
+	ti.Width = 40
+
+	ta := textarea.New()
+	ta.Placeholder = "Notes (markdown)..."
+	ta.ShowLineNumbers = false
+	ta.MaxHeight = 0
+	ta.SetHeight(10)
+	return &detailView{input: ti, notes: ta}
+}
+
+// toggleDetail opens or closes the side pane.
+func (m Model) toggleDetail() (tea.Model, tea.Cmd) {
+	if m.detail != nil {
+		m.detail = nil
+		m.mode = modeList
+		return m, nil
+	}
+	m.detail = newDetailView()
+	return m, nil
+}
+
+// focusDetail hands the keyboard to the pane, opening it if needed.
+func (m Model) focusDetail() (tea.Model, tea.Cmd) {
+	t, ok := m.detailTask()
+	if !ok {
+		return m, nil
+	}
+	if m.detail == nil {
+		m.detail = newDetailView()
+	}
+	if m.detail.taskID != t.Id {
+		m.detail.taskID = t.Id
+		m.detail.cursor = 0
+	}
+	m.mode = modeDetail
+	return m, nil
+}
+
+// detailTask returns the task shown in the pane: the selected task of the
+// focused column, if it has one.
+func (m Model) detailTask() (domain.Task, bool) {
+	col := m.focused
+	cur := m.selectedIdx[col]
+	if len(m.visibleOrder(col)) == 0 || cur < 0 || cur >= len(m.tasksByStatus[col]) {
+		return domain.Task{}, false
+	}
+	return m.tasksByStatus[col][cur], true
+}
+
+// paneWidths splits totalWidth between the board and the side pane. The
+// pane width is 0 while the pane is closed; the board width is 0 when the
+// terminal is too narrow to show both.
+func (m Model) paneWidths(totalWidth int) (board, pane int) {
+	if m.detail == nil {
+		return totalWidth, 0
+	}
+	pane = max(36, totalWidth*2/5)
+	if totalWidth-pane-1 < minPaneBoardWidth {
+		return 0, totalWidth
+	}
+	return totalWidth - pane - 1, pane
+}
+
+func (m Model) updateDetailMode(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := m.detail
+	t, ok := m.detailTask()
+	if !ok || t.Id != d.taskID {
+		// the task went away, e.g. reloaded from disk
+		m.mode = modeList
+		return m, nil
+	}
+	if d.editing {
+		return m.updateNotesEditor(key, t)
+	}
+	if d.adding {
+		switch key.Type {
+		case tea.KeyEsc:
+			d.adding = false
+			d.input.Blur()
+			return m, nil
+		case tea.KeyEnter:
+			if _, err := m.svc.AddSubtask(t.Id, d.input.Value()); err != nil {
+				m.notice = err.Error()
+				return m, nil
+			}
+			// stay in add mode so several items can be typed in a row
+			d.input.SetValue("")
+			d.cursor = len(t.Subtasks)
+			return m.setTasks(m.svc.Tasks()), nil
+		}
+		var cmd tea.Cmd
+		d.input, cmd = d.input.Update(key)
+		return m, cmd
+	}
+
+	n := len(t.Subtasks)
+	var err error
+	switch key.String() {
+	case "esc", "tab":
+		m.mode = modeList
+		return m, nil
+	case "enter":
+		return m.toggleDetail()
+	case "up", "k":
+		if d.cursor > 0 {
+			d.cursor--
+		}
+	case "down", "j":
+		if d.cursor < n-1 {
+			d.cursor++
+		}
+	case "a", "n":
+		d.adding = true
+		d.input.SetValue("")
+		d.input.Focus()
+		return m, textBlink()
+	case "e":
+		_, paneW := m.paneWidths(max(30, m.width))
+		frameW, _ := columnStyle.GetFrameSize()
+		d.editing = true
+		d.notes.SetWidth(max(10, paneW-frameW))
+		d.notes.SetValue(t.Notes)
+		return m, d.notes.Focus()
+	case "E":
+		return m.openEditor(t)
+	case " ", "x":
+		if n > 0 {
+			_, err = m.svc.ToggleSubtask(t.Id, d.cursor)
+		}
+	case "K":
+		if d.cursor > 0 {
+			if _, err = m.svc.MoveSubtask(t.Id, d.cursor, d.cursor-1); err == nil {
+				d.cursor--
+			}
+		}
+	case "J":
+		if d.cursor < n-1 {
+			if _, err = m.svc.MoveSubtask(t.Id, d.cursor, d.cursor+1); err == nil {
+				d.cursor++
+			}
+		}
+	case "d", "delete", "backspace":
+		if n > 0 {
+			_, err = m.svc.DeleteSubtask(t.Id, d.cursor)
+		}
+	case "u":
+		m = m.undo(false)
+	case "ctrl+r":
+		m = m.undo(true)
+	}
+	if err != nil {
+		m.notice = err.Error()
+		return m, nil
+	}
+	m = m.setTasks(m.svc.Tasks())
+	if t, ok := m.detailTask(); ok {
+		d.cursor = max(0, min(d.cursor, len(t.Subtasks)-1))
+	}
+	return m, nil
+}
+
+// updateNotesEditor handles keys while the notes textarea is open: ctrl+s
+// saves, esc throws the edit away, everything else is typing.
+func (m Model) updateNotesEditor(key tea.KeyMsg, t domain.Task) (tea.Model, tea.Cmd) {
+	d := m.detail
+	switch key.String() {
+	case "esc":
+		d.editing = false
+		d.notes.Blur()
+		return m, nil
+	case "ctrl+s":
+		if err := m.svc.SetNotes(t.Id, d.notes.Value()); err != nil {
+			m.notice = err.Error()
+			return m, nil
+		}
+		d.editing = false
+		d.notes.Blur()
+		m.notice = "notes saved"
+		return m.setTasks(m.svc.Tasks()), nil
+	}
+	var cmd tea.Cmd
+	d.notes, cmd = d.notes.Update(key)
+	return m, cmd
+}
+
+// renderDetail draws the side pane, at most height lines tall when height
+// is positive.
+func (m Model) renderDetail(width, height int) string {
+	frameW, frameH := columnStyle.GetFrameSize()
+	innerW := max(1, width-frameW)
+	style := unfocusedColStyle
+	if m.mode == modeDetail {
+		style = focusedColStyle
+	}
+	t, ok := m.detailTask()
+	if !ok {
+		return style.Width(innerW).Render(mutedStyle.Render("no task selected"))
+	}
+	d := m.detail
+	focused := m.mode == modeDetail
+
+	lines := strings.Split(lipgloss.NewStyle().Width(innerW).Render(headerStyle.Render(t.Content)), "\n")
+	meta := []string{statusTitle(t.Status)}
+	if t.Priority != domain.PriorityNone {
+		meta = append(meta, "priority "+task.PriorityLabel(t.Priority))
+	}
+	if t.DueAt != 0 {
+		meta = append(meta, "due "+task.FormatDue(t.DueAt))
+	}
+	if t.IsStarred {
+		meta = append(meta, "★")
+	}
+	lines = append(lines, mutedStyle.Render(strings.Join(meta, " · ")))
+	if len(t.Tags) > 0 {
+		lines = append(lines, m.renderTags(t.Tags, searchHit{tag: -1}))
+	}
+
+	done, total := task.Progress(t)
+	lines = append(lines, "", headerStyle.Render(fmt.Sprintf("Checklist [%d/%d]", done, total)))
+	if total == 0 && !d.adding {
+		lines = append(lines, mutedStyle.Render("  no items"))
+	}
+	for i, st := range t.Subtasks {
+		box := "[ ] "
+		text := st.Text
+		if st.Done {
+			box = "[x] "
+			text = doneStyle.Render(text)
+		}
+		if focused && i == d.cursor && !d.adding && !d.editing {
+			lines = append(lines, cursorBullet+" "+box+selectedTextStyle.Render(st.Text))
+		} else {
+			lines = append(lines, "  "+box+text)
+		}
+	}
+	if d.adding {
+		lines = append(lines, "  "+d.input.View())
+	}
+
+	lines = append(lines, "", headerStyle.Render("Notes"))
+	switch {
+	case d.editing:
+		lines = append(lines, d.notes.View())
+	case t.Notes == "":
+		lines = append(lines, mutedStyle.Render("  no notes"))
+	default:
+		lines = append(lines, renderMarkdown(t.Notes, innerW))
+	}
+
+	lines = append(lines, "", headerStyle.Render("Timeline"))
+	stamp := func(label string, at int64) {
+		if at != 0 {
+			lines = append(lines, mutedStyle.Render(fmt.Sprintf("  %-8s %s", label, formatStamp(at))))
+		}
+	}
+	stamp("created", t.CreatedAt)
+	stamp("started", t.StartedAt)
+	stamp("updated", t.UpdatedAt)
+	for _, c := range t.StatusHistory {
+		lines = append(lines, fmt.Sprintf("  %s %s", mutedStyle.Render(formatStamp(c.At)), statusTitle(c.Status)))
+	}
+
+	hint := "tab: focus pane · enter: close"
+	switch {
+	case d.editing:
+		hint = "ctrl+s: save notes · esc: cancel"
+	case d.adding:
+		hint = "enter: add · esc: done adding"
+	case focused:
+		hint = "e: notes · E: $EDITOR · a: add item · space: toggle · K/J: move · d: delete · tab/esc: board"
+	}
+	hint = footerStyle.Copy().MarginTop(0).Width(innerW).Render(hint)
+
+	body := strings.Split(strings.Join(lines, "\n"), "\n")
+	if height > 0 {
+		// keep the hint visible and cut the body instead
+		room := max(1, height-frameH-lipgloss.Height(hint)-1)
+		if len(body) > room {
+			cut := len(body) - room + 1
+			body = append(body[:room-1], moreLine(cut, "↓"))
+		}
+	}
+	return style.Width(innerW).Render(strings.Join(body, "\n") + "\n\n" + hint)
+}
+
+func formatStamp(at int64) string {
+	return time.Unix(at, 0).Format("2006-01-02 15:04")
+}
+
+// renderProgress is the "[2/5]" checklist marker shown on the board.
+func renderProgress(t domain.Task) string {
+	done, total := task.Progress(t)
+	if total == 0 {
+		return ""
+	}
+	style := mutedStyle
+	if done == total {
+		style = progressDoneStyle
+	}
+	return style.Render(fmt.Sprintf("[%d/%d]", done, total))
+}
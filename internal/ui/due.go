@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+// dueBadge renders the due date of t relative to now, or "" without one.
+// Done tasks only get the plain date.
+func dueBadge(t domain.Task, now time.Time) string {
+	if t.DueAt == 0 {
+		return ""
+	}
+	if t.Status == domain.TaskStatusDone {
+		return mutedStyle.Render(time.Unix(t.DueAt, 0).Format("Jan 2"))
+	}
+	state, days := task.DueIn(t.DueAt, now)
+	due := time.Unix(t.DueAt, 0)
+	switch state {
+	case task.DueOverdue:
+		return dueOverdueStyle.Render(fmt.Sprintf("overdue %dd", -days))
+	case task.DueToday:
+		return dueTodayStyle.Render("today")
+	case task.DueThisWeek:
+		if days == 1 {
+			return dueSoonStyle.Render("tomorrow")
+		}
+		return dueSoonStyle.Render(due.Format("Mon"))
+	default:
+		return mutedStyle.Render(due.Format("Jan 2"))
+	}
+}
+
+// nextSortMode cycles default → due → manual.
+func nextSortMode(mode task.SortMode) task.SortMode {
+	switch mode {
+	case task.SortDefault:
+		return task.SortDue
+	case task.SortDue:
+		return task.SortManual
+	}
+	return task.SortDefault
+}
+
+func sortModeLabel(mode task.SortMode) string {
+	switch mode {
+	case task.SortDue:
+		return "due date"
+	case task.SortManual:
+		return "manual"
+	}
+	return "priority"
+}
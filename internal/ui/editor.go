@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/editor"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+// editFile is a task written out for the external editor. It is kept after
+// an edit that does not parse, so that the next E on the same task reopens
+// it with the user's changes.
+type editFile struct {
+	taskID string
+	path   string
+}
+
+// editorFinishedMsg is sent when the editor started by openEditor exits.
+type editorFinishedMsg struct {
+	file editFile
+	err  error
+}
+
+// openEditor suspends the UI and opens t in the external editor.
+func (m Model) openEditor(t domain.Task) (tea.Model, tea.Cmd) {
+	if m.editFile != nil && m.editFile.taskID != t.Id {
+		os.Remove(m.editFile.path)
+		m.editFile = nil
+	}
+	if m.editFile == nil {
+		path, err := editor.TempFile(task.FormatEditFile(t))
+		if err != nil {
+			m.notice = err.Error()
+			return m, nil
+		}
+		m.editFile = &editFile{taskID: t.Id, path: path}
+	}
+	file := *m.editFile
+	return m, tea.ExecProcess(editor.Command(file.path), func(err error) tea.Msg {
+		return editorFinishedMsg{file: file, err: err}
+	})
+}
+
+// finishEditor applies the edited file. On a parse or validation error the
+// file is kept and the error shown, so the edit is not lost.
+func (m Model) finishEditor(msg editorFinishedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.notice = fmt.Sprintf("editor: %v", msg.err)
+		return m, nil
+	}
+	data, err := os.ReadFile(msg.file.path)
+	if err != nil {
+		m.editFile = nil
+		m.notice = fmt.Sprintf("read edit file: %v", err)
+		return m, nil
+	}
+	e, err := task.ParseEditFile(string(data), time.Now())
+	var t domain.Task
+	if err == nil {
+		t, err = m.svc.ApplyEdit(msg.file.taskID, e)
+	}
+	if err != nil {
+		m.notice = fmt.Sprintf("edit not saved: %v (E: reopen to fix)", err)
+		return m, nil
+	}
+	os.Remove(msg.file.path)
+	m.editFile = nil
+	m = m.setTasks(m.svc.Tasks())
+	m.notice = "saved: " + t.Content
+	return m, nil
+}
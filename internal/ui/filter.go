@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+// filtering reports whether any filter hides tasks from the board.
+func (m Model) filtering() bool {
+	return len(m.tagFilter) > 0 || m.filter != nil || m.searchQuery() != ""
+}
+
+// visible reports whether t passes the active filters.
+func (m Model) visible(t domain.Task) bool {
+	if len(m.tagFilter) > 0 && !task.HasAnyTag(t, m.tagFilter) {
+		return false
+	}
+	if m.filter != nil && !m.filter.Match(t, time.Now()) {
+		return false
+	}
+	if m.searchQuery() != "" {
+		if _, ok := m.matchSearch(t); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// visibleOrder is the display order of a column as indices into
+// tasksByStatus, leaving out filtered tasks.
+func (m Model) visibleOrder(status domain.TaskStatus) []int {
+	list := m.tasksByStatus[status]
+	order := task.SortedOrder(list, m.sortMode)
+	if !m.filtering() {
+		return order
+	}
+	out := order[:0]
+	for _, i := range order {
+		if m.visible(list[i]) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// syncSelection moves the cursor of every column off tasks hidden by the
+// filters, onto the first visible one.
+func (m *Model) syncSelection() {
+	for _, st := range statusOrder {
+		order := m.visibleOrder(st)
+		if len(order) == 0 || indexOf(order, m.selectedIdx[st]) != -1 {
+			continue
+		}
+		m.selectedIdx[st] = order[0]
+	}
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	mdListPattern     = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdHeadingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	mdCodeSpanPattern = regexp.MustCompile("`[^`]+`")
+	mdBoldPattern     = regexp.MustCompile(`\*\*[^*]+\*\*`)
+)
+
+// renderMarkdown renders the small subset of markdown used in notes:
+// headings, bullet and numbered lists, quotes, fenced code blocks, code
+// spans and bold text. Everything else is shown as written, wrapped to
+// width.
+func renderMarkdown(src string, width int) string {
+	width = max(1, width)
+	var out []string
+	inFence := false
+	for _, line := range strings.Split(src, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			// code keeps its layout; long lines are cut rather than wrapped
+			out = append(out, mdCodeStyle.Render(lipgloss.NewStyle().MaxWidth(width).Render("  "+line)))
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			out = append(out, "")
+			continue
+		}
+		if g := mdHeadingPattern.FindStringSubmatch(line); g != nil {
+			out = append(out, wrapHanging(mdInline(g[2], mdHeadingStyle), "", "", width)...)
+			continue
+		}
+		if g := mdListPattern.FindStringSubmatch(line); g != nil {
+			indent := strings.Repeat(" ", min(len(g[1]), 8))
+			marker := g[2]
+			if !strings.ContainsAny(marker[len(marker)-1:], ".)") {
+				marker = "•"
+			}
+			first := indent + marker + " "
+			rest := strings.Repeat(" ", lipgloss.Width(first))
+			out = append(out, wrapHanging(mdInline(g[3], lipgloss.NewStyle()), first, rest, width)...)
+			continue
+		}
+		if strings.HasPrefix(line, ">") {
+			text := strings.TrimSpace(strings.TrimPrefix(line, ">"))
+			bar := mutedStyle.Render("│ ")
+			out = append(out, wrapHanging(mdInline(text, mutedStyle), bar, bar, width)...)
+			continue
+		}
+		out = append(out, wrapHanging(mdInline(line, lipgloss.NewStyle()), "", "", width)...)
+	}
+	return strings.Join(out, "\n")
+}
+
+// mdInline styles code spans and bold text within a line; the rest is
+// rendered with base.
+func mdInline(text string, base lipgloss.Style) string {
+	var b strings.Builder
+	for text != "" {
+		code := mdCodeSpanPattern.FindStringIndex(text)
+		bold := mdBoldPattern.FindStringIndex(text)
+		span, style, trim := code, mdCodeStyle, "`"
+		if span == nil || (bold != nil && bold[0] < span[0]) {
+			span, style, trim = bold, base.Copy().Bold(true), "**"
+		}
+		if span == nil {
+			b.WriteString(base.Render(text))
+			break
+		}
+		if span[0] > 0 {
+			b.WriteString(base.Render(text[:span[0]]))
+		}
+		inner := text[span[0]+len(trim) : span[1]-len(trim)]
+		b.WriteString(style.Render(inner))
+		text = text[span[1]:]
+	}
+	return b.String()
+}
+
+// wrapHanging wraps styled text to width, starting the first line with
+// first and the following ones with rest.
+func wrapHanging(text, first, rest string, width int) []string {
+	w := max(1, width-lipgloss.Width(first))
+	lines := strings.Split(lipgloss.NewStyle().Width(w).Render(text), "\n")
+	for i, l := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		lines[i] = strings.TrimRight(prefix+l, " ")
+	}
+	return lines
+}
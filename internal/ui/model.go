@@ -4,6 +4,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/query"
 	"github.com/hungtrd/lazytodo/internal/task"
 )
 
@@ -13,6 +14,12 @@ const (
 	modeList uiMode = iota
 	modeNew
 	modeEdit
+	modeBoards
+	modeTrash
+	modeTags
+	modeSearch
+	modeQuery
+	modeDetail
 )
 
 type taskRef struct {
@@ -29,15 +36,43 @@ type Model struct {
 	tasksByStatus map[domain.TaskStatus][]domain.Task
 	selectedIdx   map[domain.TaskStatus]int
 	focused       domain.TaskStatus
+	// offsets is the first visible task of each column, as a position in
+	// its display order
+	offsets map[domain.TaskStatus]int
 
 	mode       uiMode
 	input      textinput.Model
 	editingRef *taskRef
+	picker     *boardPicker
+	// trashCursor is the selected row in the trash view
+	trashCursor int
+	tagPicker   *tagPicker
+	queryPicker *queryPicker
+	detail      *detailView
+	// editFile is the edit file of the last external edit that failed
+	editFile *editFile
+
+	// tagFilter limits the board to tasks with any of these tags
+	tagFilter map[string]bool
+	tagColors map[string]string
+	// filter is the active query filter; filterName names it when it
+	// came from a saved query
+	filter     *query.Query
+	filterName string
+	// search holds the fuzzy search text; it filters the board while
+	// non-empty
+	search textinput.Model
 
 	vertical bool
+	sortMode task.SortMode
+	// notice is a one-line message shown until the next key press
+	notice string
+
+	// changes signals that the tasks file was modified on disk
+	changes <-chan struct{}
 }
 
-func InitialModel(svc *task.Service) Model {
+func InitialModel(svc *task.Service, changes <-chan struct{}) Model {
 	ti := textinput.New()
 	ti.Placeholder = "Task content..."
 	ti.Prompt = "➤ "
@@ -54,6 +89,8 @@ func InitialModel(svc *task.Service) Model {
 		focused: domain.TaskStatusTodo,
 		mode:    modeList,
 		input:   ti,
+		search:  newSearchInput(),
+		changes: changes,
 	}
 	// load data
 	if tasks, err := svc.Load(); err == nil {
@@ -62,7 +99,15 @@ func InitialModel(svc *task.Service) Model {
 	if v, err := svc.GetLayoutVertical(); err == nil {
 		m.vertical = v
 	}
+	if colors, err := svc.TagColors(); err == nil {
+		m.tagColors = colors
+	}
+	if mode, err := svc.GetSortMode(); err == nil {
+		m.sortMode = mode
+	} else {
+		m.sortMode = task.SortDefault
+	}
 	return m
 }
 
-func (m Model) Init() tea.Cmd { return nil }
+func (m Model) Init() tea.Cmd { return waitForChange(m.changes) }
@@ -1,27 +1,22 @@
 package ui
 
 import (
-	"time"
-
 	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/task"
 )
 
 func (m Model) moveTask(from domain.TaskStatus, index int, to domain.TaskStatus) Model {
 	if from == to { return m }
 	list := m.tasksByStatus[from]
 	if index < 0 || index >= len(list) { return m }
-	task := list[index]
-	_ = m.svc.Move(task.Id, to)
-	// update local state similarly
-	m.tasksByStatus[from] = append(list[:index], list[index+1:]...)
-	task.Status = to
-	task.UpdatedAt = time.Now().Unix()
-	m.tasksByStatus[to] = append([]domain.Task{task}, m.tasksByStatus[to]...)
-	if index >= len(m.tasksByStatus[from]) {
-		m.selectedIdx[from] = max(0, len(m.tasksByStatus[from])-1)
+	id := list[index].Id
+	if err := m.svc.Move(id, to); err != nil {
+		m.notice = err.Error()
+		return m
 	}
+	m = m.setTasks(m.svc.Tasks())
 	m.focused = to
-	m.selectedIdx[to] = 0
+	m.selectedIdx[to] = max(0, indexByID(m.tasksByStatus[to], id))
 	return m
 }
 
@@ -33,3 +28,31 @@ func (m *Model) deleteTask(status domain.TaskStatus, index int) {
 		m.selectedIdx[status] = max(0, len(m.tasksByStatus[status])-1)
 	}
 }
+
+// reorder swaps the task at index with its visible neighbour above (or
+// below, with down set) in manual order.
+func (m Model) reorder(status domain.TaskStatus, index int, down bool) Model {
+	if m.sortMode != task.SortManual {
+		m.notice = "press o until the sort is manual to reorder tasks"
+		return m
+	}
+	order := m.visibleOrder(status)
+	pos := indexOf(order, index)
+	next := pos - 1
+	if down {
+		next = pos + 1
+	}
+	if pos == -1 || next < 0 || next >= len(order) {
+		return m
+	}
+	list := m.tasksByStatus[status]
+	// the neighbour's place in the full column, which may hold filtered tasks
+	target := indexOf(task.SortedOrder(list, task.SortManual), order[next])
+	id := list[index].Id
+	if err := m.svc.Reorder(id, target); err != nil {
+		m.notice = err.Error()
+		return m
+	}
+	m = m.setTasks(m.svc.Tasks())
+	return m
+}
@@ -0,0 +1,30 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+func TestMoveTaskShowsServiceState(t *testing.T) {
+	m := newTestModel(t)
+	for _, text := range []string{"first", "second"} {
+		if _, err := m.svc.Add(text); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m = m.setTasks(m.svc.Tasks())
+
+	m = m.moveTask(domain.TaskStatusTodo, 1, domain.TaskStatusDone)
+	if !reflect.DeepEqual(m.tasksByStatus, m.svc.Tasks()) {
+		t.Fatalf("board %+v, want the service state %+v", m.tasksByStatus, m.svc.Tasks())
+	}
+	done := m.tasksByStatus[domain.TaskStatusDone]
+	if len(done) != 1 || done[0].Content != "first" || len(done[0].StatusHistory) != 2 {
+		t.Fatalf("done column %+v, want the moved task with its status history", done)
+	}
+	if m.focused != domain.TaskStatusDone || m.selectedIdx[domain.TaskStatusDone] != 0 {
+		t.Fatalf("focus on %d at %d, want the moved task", m.focused, m.selectedIdx[domain.TaskStatusDone])
+	}
+}
@@ -0,0 +1,30 @@
+package ui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+// priorityMarkers are two cells wide like the star slot, so titles stay
+// aligned whatever the priority. High and urgent differ by color.
+var priorityMarkers = map[domain.Priority]string{
+	domain.PriorityLow:    "↓ ",
+	domain.PriorityMedium: "! ",
+	domain.PriorityHigh:   "!!",
+	domain.PriorityUrgent: "!!",
+}
+
+var priorityStyles = map[domain.Priority]lipgloss.Style{
+	domain.PriorityLow:    lipgloss.NewStyle().Foreground(lipgloss.Color("75")),
+	domain.PriorityMedium: lipgloss.NewStyle().Foreground(lipgloss.Color("220")),
+	domain.PriorityHigh:   lipgloss.NewStyle().Foreground(lipgloss.Color("208")),
+	domain.PriorityUrgent: lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true),
+}
+
+func priorityMarker(p domain.Priority) string {
+	marker, ok := priorityMarkers[p]
+	if !ok {
+		return "  "
+	}
+	return priorityStyles[p].Render(marker)
+}
@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hungtrd/lazytodo/internal/query"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+// queryPicker is the filter prompt: a query input above the saved queries.
+type queryPicker struct {
+	input  textinput.Model
+	saved  []task.SavedQuery
+	cursor int
+	err    string
+}
+
+func (m Model) openQueryPrompt() (tea.Model, tea.Cmd) {
+	ti := textinput.New()
+	ti.Placeholder = "status:todo due:<7d -tag:blocked"
+	ti.Prompt = "➤ "
+	ti.CharLimit = 256
+	ti.Width = 48
+	ti.Focus()
+	p := &queryPicker{input: ti}
+	saved, err := m.svc.SavedQueries()
+	if err != nil {
+		p.err = err.Error()
+	}
+	p.saved = saved
+	m.queryPicker = p
+	m.mode = modeQuery
+	return m, textBlink()
+}
+
+func (m Model) updateQueryMode(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.queryPicker
+	switch key.String() {
+	case "esc":
+		m.mode = modeList
+		m.queryPicker = nil
+		return m, nil
+	case "up", "ctrl+p":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return m, nil
+	case "down", "ctrl+n":
+		if p.cursor < len(p.saved)-1 {
+			p.cursor++
+		}
+		return m, nil
+	case "tab":
+		// copy the highlighted query into the input for editing
+		if len(p.saved) > 0 {
+			p.input.SetValue(p.saved[p.cursor].Query)
+			p.input.CursorEnd()
+		}
+		return m, nil
+	case "ctrl+d":
+		if len(p.saved) == 0 {
+			return m, nil
+		}
+		name := p.saved[p.cursor].Name
+		if err := m.svc.DeleteQuery(name); err != nil {
+			p.err = err.Error()
+			return m, nil
+		}
+		p.saved = append(p.saved[:p.cursor], p.saved[p.cursor+1:]...)
+		p.cursor = min(p.cursor, max(0, len(p.saved)-1))
+		m.notice = "deleted query " + name
+		return m, nil
+	case "enter":
+		text := strings.TrimSpace(p.input.Value())
+		name := ""
+		if text == "" {
+			if len(p.saved) == 0 {
+				m = m.setQueryFilter(nil, "")
+				m.mode = modeList
+				m.queryPicker = nil
+				return m, nil
+			}
+			name, text = p.saved[p.cursor].Name, p.saved[p.cursor].Query
+		}
+		save := false
+		if n, q, ok := strings.Cut(text, "="); ok && !strings.ContainsAny(n, ":\"") {
+			name, text, save = strings.TrimSpace(n), strings.TrimSpace(q), true
+		}
+		q, err := query.Parse(text)
+		if err != nil {
+			p.err = err.Error()
+			return m, nil
+		}
+		if save {
+			if err := m.svc.SaveQuery(name, text); err != nil {
+				p.err = err.Error()
+				return m, nil
+			}
+			m.notice = "saved query " + name
+		}
+		m = m.setQueryFilter(q, name)
+		m.mode = modeList
+		m.queryPicker = nil
+		return m, nil
+	}
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(key)
+	p.err = ""
+	return m, cmd
+}
+
+// setQueryFilter limits the board to tasks matching q; nil shows
+// everything. name is the saved query q came from, if any.
+func (m Model) setQueryFilter(q *query.Query, name string) Model {
+	m.filter, m.filterName = nil, ""
+	if q != nil && q.String() != "" {
+		m.filter, m.filterName = q, name
+	}
+	m.syncSelection()
+	return m
+}
+
+// queryFilterLabel describes the active query filter for the title.
+func (m Model) queryFilterLabel() string {
+	if m.filterName != "" {
+		return "view: " + m.filterName
+	}
+	return "filter: " + m.filter.String()
+}
+
+// renderQueryPrompt draws the filter prompt in place of the columns
+func (m Model) renderQueryPrompt(totalWidth int) string {
+	p := m.queryPicker
+	lines := []string{headerStyle.Render("Filter"), p.input.View()}
+	if p.err != "" {
+		lines = append(lines, errorStyle.Render(p.err))
+	}
+	if m.filter != nil {
+		lines = append(lines, mutedStyle.Render("active: "+m.filter.String()))
+	}
+	lines = append(lines, "")
+	if len(p.saved) == 0 {
+		lines = append(lines, mutedStyle.Render("  no saved queries; type name = query to save one"))
+	}
+	for i, sq := range p.saved {
+		label := sq.Name + mutedStyle.Render("  "+sq.Query)
+		if i == p.cursor {
+			lines = append(lines, cursorBullet+" "+selectedTextStyle.Render(sq.Name)+mutedStyle.Render("  "+sq.Query))
+		} else {
+			lines = append(lines, "  "+label)
+		}
+	}
+	lines = append(lines, "", footerStyle.Copy().MarginTop(0).Render("enter: apply (empty: highlighted) · tab: edit highlighted · ctrl+d: delete · esc: cancel"))
+	frameW, _ := columnStyle.GetFrameSize()
+	w := min(max(40, totalWidth*2/3), totalWidth) - frameW
+	box := focusedColStyle.Width(max(1, w)).Render(strings.Join(lines, "\n"))
+	return lipgloss.PlaceHorizontal(totalWidth, lipgloss.Center, box)
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+// tasksChangedMsg is sent when the tasks file changed on disk.
+type tasksChangedMsg struct{}
+
+// waitForChange blocks until the next change notification. It yields nil
+// once the channel is closed, which ends the loop.
+func waitForChange(changes <-chan struct{}) tea.Cmd {
+	if changes == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if _, ok := <-changes; !ok {
+			return nil
+		}
+		return tasksChangedMsg{}
+	}
+}
+
+// reloadTasks refreshes the service cache and the board from storage.
+func (m Model) reloadTasks() Model {
+	tasks, err := m.svc.Load()
+	if err != nil {
+		m.notice = err.Error()
+		return m
+	}
+	return m.setTasks(tasks)
+}
+
+// setTasks replaces the board's tasks, keeping the selection in each column
+// on the same task where it still exists there.
+func (m Model) setTasks(tasks map[domain.TaskStatus][]domain.Task) Model {
+	selectedIDs := make(map[domain.TaskStatus]string, len(m.selectedIdx))
+	for st, idx := range m.selectedIdx {
+		if list := m.tasksByStatus[st]; idx >= 0 && idx < len(list) {
+			selectedIDs[st] = list[idx].Id
+		}
+	}
+	var editingID string
+	if m.editingRef != nil {
+		if list := m.tasksByStatus[m.editingRef.status]; m.editingRef.index < len(list) {
+			editingID = list[m.editingRef.index].Id
+		}
+	}
+
+	m.tasksByStatus = tasks
+	for _, st := range statusOrder {
+		list := m.tasksByStatus[st]
+		if idx := indexByID(list, selectedIDs[st]); idx >= 0 {
+			m.selectedIdx[st] = idx
+		} else if m.selectedIdx[st] >= len(list) {
+			m.selectedIdx[st] = max(0, len(list)-1)
+		}
+	}
+	if m.editingRef != nil {
+		st, idx := findByID(m.tasksByStatus, editingID)
+		if idx == -1 {
+			// the task being edited is gone; drop the edit
+			m.mode = modeList
+			m.editingRef = nil
+			m.input.Blur()
+		} else {
+			m.editingRef = &taskRef{status: st, index: idx}
+		}
+	}
+	m.syncSelection()
+	return m
+}
+
+func indexByID(list []domain.Task, id string) int {
+	if id == "" {
+		return -1
+	}
+	for i := range list {
+		if list[i].Id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func findByID(tasks map[domain.TaskStatus][]domain.Task, id string) (domain.TaskStatus, int) {
+	for _, st := range statusOrder {
+		if idx := indexByID(tasks[st], id); idx >= 0 {
+			return st, idx
+		}
+	}
+	return domain.TaskStatusTodo, -1
+}
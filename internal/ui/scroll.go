@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+// renderFooter draws everything below the board: the notice, the prompt of
+// the input modes and the help.
+func (m Model) renderFooter(totalWidth int) string {
+	help := m.renderHelp(totalWidth)
+	if m.notice != "" {
+		help = noticeStyle.Render(m.notice) + "\n" + help
+	}
+	switch m.mode {
+	case modeNew:
+		prompt := footerStyle.Copy().Bold(true).Render("New Task:")
+		return prompt + "\n" + m.input.View() + "\n" + help
+	case modeEdit:
+		prompt := footerStyle.Copy().Bold(true).Render("Edit Task:")
+		return prompt + "\n" + m.input.View() + "\n" + help
+	case modeSearch:
+		prompt := footerStyle.Copy().Bold(true).Render("Search:")
+		return prompt + "\n" + m.search.View() + "\n" + help
+	}
+	return help
+}
+
+// itemRows returns how many lines each column has for its tasks, sharing
+// the rows left between the title and the footer. A negative count means
+// the terminal size is not known yet and nothing is cut.
+func (m Model) itemRows() map[domain.TaskStatus]int {
+	rows := make(map[domain.TaskStatus]int, len(statusOrder))
+	if m.height <= 0 {
+		for _, st := range statusOrder {
+			rows[st] = -1
+		}
+		return rows
+	}
+	_, frameH := columnStyle.GetFrameSize()
+	// the title line, and a header line in each column
+	avail := m.height - 1 - lipgloss.Height(m.renderFooter(max(30, m.width)))
+	if !m.vertical {
+		for _, st := range statusOrder {
+			rows[st] = max(1, avail-frameH-1)
+		}
+		return rows
+	}
+	// stacked columns: short columns take what they need and the rest is
+	// shared evenly, any remainder going to the focused column
+	avail = max(len(statusOrder), avail-len(statusOrder)*(frameH+1))
+	byNeed := append([]domain.TaskStatus(nil), statusOrder...)
+	need := make(map[domain.TaskStatus]int, len(byNeed))
+	for _, st := range byNeed {
+		need[st] = max(1, len(m.visibleOrder(st)))
+	}
+	sort.SliceStable(byNeed, func(i, j int) bool { return need[byNeed[i]] < need[byNeed[j]] })
+	for i, st := range byNeed {
+		share := avail / (len(byNeed) - i)
+		rows[st] = min(need[st], share)
+		avail -= rows[st]
+	}
+	rows[m.focused] += avail
+	return rows
+}
+
+// scrollOffset returns the first task shown in a column of total tasks
+// with room for rows lines, moving offset just enough to show the task at
+// pos. Once a column scrolls, two of its lines hold the "more" markers.
+func scrollOffset(offset, pos, total, rows int) int {
+	if rows < 0 || total <= rows {
+		return 0
+	}
+	window := max(1, rows-2)
+	if pos < offset {
+		offset = pos
+	}
+	if pos >= offset+window {
+		offset = pos - window + 1
+	}
+	return max(0, min(offset, total-window))
+}
+
+// scrollToSelection updates every column's offset so its selected task is
+// on screen.
+func (m *Model) scrollToSelection() {
+	if m.offsets == nil {
+		m.offsets = map[domain.TaskStatus]int{}
+	}
+	rows := m.itemRows()
+	for _, st := range statusOrder {
+		order := m.visibleOrder(st)
+		pos := max(0, indexOf(order, m.selectedIdx[st]))
+		m.offsets[st] = scrollOffset(m.offsets[st], pos, len(order), rows[st])
+	}
+}
+
+// scrollItems cuts a column's rendered tasks down to the rows it has,
+// adding "↑ N more" / "↓ N more" markers, and keeps each task on one line
+// of at most width cells.
+func (m Model) scrollItems(status domain.TaskStatus, lines []string, rows, width int) []string {
+	clip := lipgloss.NewStyle().MaxWidth(max(1, width))
+	if rows < 0 || len(lines) <= rows {
+		out := make([]string, len(lines))
+		for i, l := range lines {
+			out[i] = clip.Render(l)
+		}
+		return out
+	}
+	window := max(1, rows-2)
+	pos := max(0, indexOf(m.visibleOrder(status), m.selectedIdx[status]))
+	offset := scrollOffset(m.offsets[status], pos, len(lines), rows)
+	end := min(len(lines), offset+window)
+	out := make([]string, 0, rows)
+	if rows > 2 {
+		out = append(out, moreLine(offset, "↑"))
+	}
+	for _, l := range lines[offset:end] {
+		out = append(out, clip.Render(l))
+	}
+	if rows > 2 {
+		out = append(out, moreLine(len(lines)-end, "↓"))
+	}
+	return out
+}
+
+func moreLine(n int, arrow string) string {
+	if n <= 0 {
+		return ""
+	}
+	return mutedStyle.Render(fmt.Sprintf("  %s %d more", arrow, n))
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+func TestScrollOffset(t *testing.T) {
+	tests := []struct {
+		name                     string
+		offset, pos, total, rows int
+		want                     int
+	}{
+		{"size unknown", 4, 9, 10, -1, 0},
+		{"everything fits", 3, 4, 5, 5, 0},
+		{"selection on screen", 2, 3, 10, 5, 2},
+		{"selection above", 5, 1, 10, 5, 1},
+		// five rows leave three for tasks next to the markers
+		{"selection below", 0, 6, 10, 5, 4},
+		{"stale offset past the end", 9, 9, 10, 5, 7},
+		{"too short for markers", 0, 4, 10, 2, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scrollOffset(tt.offset, tt.pos, tt.total, tt.rows); got != tt.want {
+				t.Fatalf("scrollOffset(%d, %d, %d, %d) = %d, want %d", tt.offset, tt.pos, tt.total, tt.rows, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrollItems(t *testing.T) {
+	m := newTestModel(t)
+	var lines []string
+	for i := 0; i < 10; i++ {
+		if _, err := m.svc.Add(fmt.Sprintf("task %d", i)); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	m = m.setTasks(m.svc.Tasks())
+	m.selectedIdx[domain.TaskStatusTodo] = m.visibleOrder(domain.TaskStatusTodo)[5]
+	m.scrollToSelection()
+
+	out := m.scrollItems(domain.TaskStatusTodo, lines, 5, 40)
+	if len(out) != 5 {
+		t.Fatalf("%d lines, want 5", len(out))
+	}
+	if !strings.Contains(out[0], "↑ 3 more") || !strings.Contains(out[4], "↓ 4 more") {
+		t.Fatalf("markers %q and %q, want 3 above and 4 below", out[0], out[4])
+	}
+	if out[1] != "line 3" || out[3] != "line 5" {
+		t.Fatalf("window %q, want lines 3 to 5", out[1:4])
+	}
+}
@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/fuzzy"
+)
+
+// searchHit is where a task matched the search: rune positions in the
+// content, or in one of its tags when the content did not match.
+type searchHit struct {
+	content []int
+	tag     int
+	tagPos  []int
+}
+
+func newSearchInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "fuzzy search content and #tags..."
+	ti.Prompt = "/ "
+	ti.CharLimit = 64
+	ti.Width = 40
+	return ti
+}
+
+// searchQuery is the active search text, empty when not searching.
+func (m Model) searchQuery() string {
+	return strings.TrimSpace(m.search.Value())
+}
+
+// matchSearch fuzzy-matches the search against t's content, then its tags.
+func (m Model) matchSearch(t domain.Task) (searchHit, bool) {
+	q := strings.ReplaceAll(m.searchQuery(), " ", "")
+	if pos, ok := fuzzy.Match(q, t.Content); ok {
+		return searchHit{content: pos, tag: -1}, true
+	}
+	for i, tag := range t.Tags {
+		if pos, ok := fuzzy.Match(strings.TrimPrefix(q, "#"), tag); ok {
+			return searchHit{tag: i, tagPos: pos}, true
+		}
+	}
+	return searchHit{}, false
+}
+
+func (m Model) openSearch() (tea.Model, tea.Cmd) {
+	m.mode = modeSearch
+	m.search.CursorEnd()
+	m.search.Focus()
+	return m, textBlink()
+}
+
+func (m Model) updateSearchMode(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key.Type {
+	case tea.KeyEsc:
+		m.mode = modeList
+		m.search.Blur()
+		m.search.SetValue("")
+		m.syncSelection()
+		return m, nil
+	case tea.KeyEnter:
+		m.mode = modeList
+		m.search.Blur()
+		if m.searchQuery() == "" {
+			m.search.SetValue("")
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.search, cmd = m.search.Update(key)
+	m.syncSelection()
+	if len(m.visibleOrder(m.focused)) == 0 {
+		m = m.jumpHit(1)
+	}
+	return m, cmd
+}
+
+// hits lists every visible task in board order: column by column, top to
+// bottom.
+func (m Model) hits() []taskRef {
+	var out []taskRef
+	for _, st := range statusOrder {
+		for _, i := range m.visibleOrder(st) {
+			out = append(out, taskRef{status: st, index: i})
+		}
+	}
+	return out
+}
+
+// jumpHit moves the cursor to the next (dir > 0) or previous search hit,
+// wrapping around and crossing columns.
+func (m Model) jumpHit(dir int) Model {
+	hits := m.hits()
+	if len(hits) == 0 {
+		return m
+	}
+	cur := -1
+	for i, h := range hits {
+		if h.status == m.focused && h.index == m.selectedIdx[m.focused] {
+			cur = i
+			break
+		}
+	}
+	next := 0
+	if cur != -1 {
+		next = (cur + dir + len(hits)) % len(hits)
+	} else if dir < 0 {
+		next = len(hits) - 1
+	}
+	h := hits[next]
+	m.focused = h.status
+	m.selectedIdx[h.status] = h.index
+	if m.searchQuery() != "" {
+		m.notice = fmt.Sprintf("match %d/%d", next+1, len(hits))
+	}
+	return m
+}
+
+// highlight renders text with the runes at positions marked as matches.
+func highlight(text string, positions []int, base lipgloss.Style) string {
+	if len(positions) == 0 {
+		return base.Render(text)
+	}
+	match := searchMatchStyle.Copy().Inherit(base)
+	var b strings.Builder
+	runes := []rune(text)
+	p := 0
+	start := 0
+	for i := range runes {
+		if p < len(positions) && positions[p] == i {
+			b.WriteString(base.Render(string(runes[start:i])))
+			b.WriteString(match.Render(string(runes[i])))
+			start = i + 1
+			p++
+		}
+	}
+	b.WriteString(base.Render(string(runes[start:])))
+	return b.String()
+}
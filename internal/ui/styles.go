@@ -3,6 +3,8 @@ package ui
 import "github.com/charmbracelet/lipgloss"
 
 var (
+	titleStyle          = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("229"))
+	boardNameStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
 	headerStyle         = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
 	columnStyle         = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1, 1)
 	focusedColStyle     = columnStyle.Copy().BorderForeground(lipgloss.Color("12"))
@@ -11,7 +13,18 @@ var (
 	selectedLineBgStyle = lipgloss.NewStyle().Background(lipgloss.Color("236"))
 	selectedTextStyle   = lipgloss.NewStyle().Background(lipgloss.Color("236")).Foreground(lipgloss.Color("229")).Bold(true)
 	starredStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	mutedStyle          = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
 	doneStyle           = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Strikethrough(true)
+	dueOverdueStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true)
+	dueTodayStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	dueSoonStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("228"))
+	tagChipStyle        = lipgloss.NewStyle()
+	progressDoneStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	searchMatchStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Bold(true).Underline(true)
+	mdHeadingStyle      = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("229"))
+	mdCodeStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("180"))
+	errorStyle          = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	noticeStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).MarginTop(1)
 	footerStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).MarginTop(1)
 	cursorBullet        = "•"
 )
@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+// tagPalette holds the colors tags are hashed onto when the config does not
+// name one.
+var tagPalette = []lipgloss.Color{"39", "170", "114", "208", "141", "81", "204", "178", "75", "150"}
+
+// tagStyle returns the chip style for a tag: the color from tag_colors in
+// the config, or one derived from the name so it never changes.
+func (m Model) tagStyle(name string) lipgloss.Style {
+	color, ok := m.tagColors[name]
+	if !ok {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(name))
+		return tagChipStyle.Foreground(tagPalette[h.Sum32()%uint32(len(tagPalette))])
+	}
+	return tagChipStyle.Foreground(lipgloss.Color(color))
+}
+
+// renderTags draws the chips of a task, highlighting the tag hit matched.
+func (m Model) renderTags(tags []string, hit searchHit) string {
+	chips := make([]string, len(tags))
+	for i, tag := range tags {
+		if i == hit.tag {
+			chips[i] = m.tagStyle(tag).Render("#") + highlight(tag, hit.tagPos, m.tagStyle(tag))
+			continue
+		}
+		chips[i] = m.tagStyle(tag).Render("#" + tag)
+	}
+	return strings.Join(chips, " ")
+}
+
+// tagPicker is the overlay for choosing which tags the board shows.
+type tagPicker struct {
+	tags     []task.TagCount
+	selected map[string]bool
+	cursor   int
+}
+
+func (m Model) openTagFilter() (tea.Model, tea.Cmd) {
+	p := &tagPicker{tags: task.BoardTags(m.tasksByStatus), selected: map[string]bool{}}
+	for tag := range m.tagFilter {
+		p.selected[tag] = true
+	}
+	m.tagPicker = p
+	m.mode = modeTags
+	return m, nil
+}
+
+func (m Model) updateTagMode(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.tagPicker
+	switch key.String() {
+	case "esc", "#":
+		m.mode = modeList
+		m.tagPicker = nil
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(p.tags)-1 {
+			p.cursor++
+		}
+	case " ", "x":
+		if len(p.tags) == 0 {
+			return m, nil
+		}
+		name := p.tags[p.cursor].Name
+		if p.selected[name] {
+			delete(p.selected, name)
+		} else {
+			p.selected[name] = true
+		}
+	case "c":
+		p.selected = map[string]bool{}
+	case "enter":
+		m = m.setTagFilter(p.selected)
+		m.mode = modeList
+		m.tagPicker = nil
+	}
+	return m, nil
+}
+
+// setTagFilter shows only tasks carrying at least one of tags; an empty set
+// shows everything.
+func (m Model) setTagFilter(tags map[string]bool) Model {
+	m.tagFilter = nil
+	if len(tags) > 0 {
+		m.tagFilter = tags
+	}
+	m.syncSelection()
+	return m
+}
+
+// tagFilterLabel lists the active tag filter for the title.
+func (m Model) tagFilterLabel() string {
+	names := make([]string, 0, len(m.tagFilter))
+	for tag := range m.tagFilter {
+		names = append(names, "#"+tag)
+	}
+	sort.Strings(names)
+	return strings.Join(names, " ")
+}
+
+// renderTagPicker draws the tag list in place of the columns
+func (m Model) renderTagPicker(totalWidth int) string {
+	p := m.tagPicker
+	lines := []string{headerStyle.Render("Filter by tags"), ""}
+	if len(p.tags) == 0 {
+		lines = append(lines, mutedStyle.Render("  no tagged tasks; add #tags to task text"))
+	}
+	for i, tc := range p.tags {
+		box := "[ ]"
+		if p.selected[tc.Name] {
+			box = "[x]"
+		}
+		label := fmt.Sprintf("%s %s", box, m.tagStyle(tc.Name).Render("#"+tc.Name)) + mutedStyle.Render(fmt.Sprintf(" (%d)", tc.Count))
+		if i == p.cursor {
+			lines = append(lines, cursorBullet+" "+label)
+		} else {
+			lines = append(lines, "  "+label)
+		}
+	}
+	lines = append(lines, "", footerStyle.Copy().MarginTop(0).Render("space: toggle · c: clear · enter: apply · esc: cancel"))
+	frameW, _ := columnStyle.GetFrameSize()
+	w := min(max(30, totalWidth/2), totalWidth) - frameW
+	box := focusedColStyle.Width(max(1, w)).Render(strings.Join(lines, "\n"))
+	return lipgloss.PlaceHorizontal(totalWidth, lipgloss.Center, box)
+}
@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/hungtrd/lazytodo/internal/repository"
+	"github.com/hungtrd/lazytodo/internal/task"
+)
+
+// undo reverts (or with redo set, re-applies) the last mutation and puts
+// the cursor on the task it touched.
+func (m Model) undo(redo bool) Model {
+	var entry repository.HistoryEntry
+	var err error
+	verb := "undid"
+	if redo {
+		entry, err = m.svc.Redo()
+		verb = "redid"
+	} else {
+		entry, err = m.svc.Undo()
+	}
+	if err != nil {
+		m.notice = err.Error()
+		return m
+	}
+	m = m.setTasks(m.svc.Tasks())
+	if t, ok := task.Subject(entry, !redo); ok {
+		m.notice = fmt.Sprintf("%s %s: %s", verb, entry.Op, t.Content)
+		if st, idx := findByID(m.tasksByStatus, t.Id); idx != -1 {
+			m.focused = st
+			m.selectedIdx[st] = idx
+		}
+	}
+	return m
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hungtrd/lazytodo/internal/domain"
+	"github.com/hungtrd/lazytodo/internal/testutil"
+)
+
+func newTestModel(t *testing.T) Model {
+	t.Helper()
+	return InitialModel(testutil.TempService(t), nil)
+}
+
+func TestUndoRedo(t *testing.T) {
+	m := newTestModel(t)
+	added, err := m.svc.Add("first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.svc.UpdateContent(added.Id, "second"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.svc.UpdateContent(added.Id, "third"); err != nil {
+		t.Fatal(err)
+	}
+	m = m.setTasks(m.svc.Tasks())
+
+	content := func(m Model) string {
+		t.Helper()
+		todo := m.svc.Tasks()[domain.TaskStatusTodo]
+		if len(todo) != 1 {
+			t.Fatalf("got %d todo tasks, want 1", len(todo))
+		}
+		return todo[0].Content
+	}
+	steps := []struct {
+		redo    bool
+		content string
+		notice  string
+	}{
+		{false, "second", "undid"},
+		{false, "first", "undid"},
+		{true, "second", "redid"},
+		{true, "third", "redid"},
+		{false, "second", "undid"},
+	}
+	for i, s := range steps {
+		m = m.undo(s.redo)
+		if got := content(m); got != s.content {
+			t.Fatalf("step %d: content %q, want %q", i, got, s.content)
+		}
+		if !strings.HasPrefix(m.notice, s.notice+" ") {
+			t.Fatalf("step %d: notice %q, want it to start with %q", i, m.notice, s.notice)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hungtrd/lazytodo/internal/domain"
+)
+
+type boardAction int
+
+const (
+	boardSwitch boardAction = iota
+	boardMoveTask
+)
+
+// boardPicker is the overlay listing boards. Typing filters the list;
+// a name that matches no board is created on enter.
+type boardPicker struct {
+	action boardAction
+	names  []string
+	cursor int
+	input  textinput.Model
+	err    string
+}
+
+func newBoardPicker(action boardAction, names []string) *boardPicker {
+	ti := textinput.New()
+	ti.Placeholder = "filter or new board name..."
+	ti.Prompt = "➤ "
+	ti.CharLimit = 32
+	ti.Focus()
+	return &boardPicker{action: action, names: names, input: ti}
+}
+
+// matches returns the boards whose name contains the typed text.
+func (p *boardPicker) matches() []string {
+	q := strings.ToLower(strings.TrimSpace(p.input.Value()))
+	if q == "" {
+		return p.names
+	}
+	out := make([]string, 0, len(p.names))
+	for _, n := range p.names {
+		if strings.Contains(strings.ToLower(n), q) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// choice is the board enter would pick: the highlighted match, or the typed
+// name when nothing matches.
+func (p *boardPicker) choice() string {
+	matches := p.matches()
+	if len(matches) == 0 {
+		return strings.TrimSpace(p.input.Value())
+	}
+	if p.cursor >= len(matches) {
+		p.cursor = len(matches) - 1
+	}
+	return matches[p.cursor]
+}
+
+func (m Model) openBoardPicker(action boardAction) (tea.Model, tea.Cmd) {
+	if !m.svc.HasBoards() {
+		return m, nil
+	}
+	if action == boardMoveTask && len(m.visibleOrder(m.focused)) == 0 {
+		return m, nil
+	}
+	names, _ := m.svc.Boards()
+	m.picker = newBoardPicker(action, names)
+	m.mode = modeBoards
+	return m, textBlink()
+}
+
+func (m Model) updateBoardMode(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.picker
+	switch key.String() {
+	case "esc":
+		m.mode = modeList
+		m.picker = nil
+		return m, nil
+	case "up", "ctrl+p", "shift+tab":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return m, nil
+	case "down", "ctrl+n", "tab":
+		if p.cursor < len(p.matches())-1 {
+			p.cursor++
+		}
+		return m, nil
+	case "enter":
+		name := p.choice()
+		if name == "" {
+			return m, nil
+		}
+		var err error
+		if p.action == boardMoveTask {
+			err = m.moveTaskToBoard(name)
+		} else {
+			err = m.switchBoard(name)
+		}
+		if err != nil {
+			p.err = err.Error()
+			return m, nil
+		}
+		m.mode = modeList
+		m.picker = nil
+		return m, nil
+	}
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(key)
+	p.cursor = 0
+	p.err = ""
+	return m, cmd
+}
+
+func (m *Model) switchBoard(name string) error {
+	tasks, err := m.svc.SwitchBoard(name)
+	if tasks == nil {
+		return err
+	}
+	m.tasksByStatus = tasks
+	for _, st := range statusOrder {
+		m.selectedIdx[st] = 0
+	}
+	m.focused = domain.TaskStatusTodo
+	return err
+}
+
+func (m *Model) moveTaskToBoard(name string) error {
+	col := m.focused
+	cur := m.selectedIdx[col]
+	items := m.tasksByStatus[col]
+	if cur < 0 || cur >= len(items) {
+		return nil
+	}
+	if err := m.svc.MoveToBoard(items[cur].Id, name); err != nil {
+		return err
+	}
+	m.deleteTask(col, cur)
+	return nil
+}
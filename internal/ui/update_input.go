@@ -18,14 +18,22 @@ func (m Model) updateInputMode(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 		content := strings.TrimSpace(m.input.Value())
 		if content != "" {
 			if m.mode == modeNew {
-				if t, err := m.svc.Add(content); err == nil {
-					m.addTaskToState(t)
+				t, err := m.svc.Add(content)
+				if err != nil {
+					// keep the prompt open so the text can be fixed
+					m.notice = err.Error()
+					return m, nil
 				}
+				m.addTaskToState(t)
 			} else if m.mode == modeEdit && m.editingRef != nil {
 				ref := *m.editingRef
 				t := m.tasksByStatus[ref.status][ref.index]
-				_ = m.svc.UpdateContent(t.Id, content)
-				m.tasksByStatus[ref.status][ref.index].Content = content
+				if err := m.svc.UpdateContent(t.Id, content); err != nil {
+					m.notice = err.Error()
+					return m, nil
+				}
+				m.editingRef = nil
+				m = m.setTasks(m.svc.Tasks())
 			}
 		}
 		m.mode = modeList
@@ -45,4 +53,5 @@ func (m *Model) addTaskToState(t domain.Task) {
 		m.selectedIdx = map[domain.TaskStatus]int{}
 	}
 	m.selectedIdx[domain.TaskStatusTodo] = 0
+	m.syncSelection()
 }
@@ -7,19 +7,47 @@ import (
 )
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if nm, ok := next.(Model); ok {
+		// keep the selection on screen after whatever moved it
+		nm.scrollToSelection()
+		return nm, cmd
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width, m.height = msg.Width, msg.Height
 		return m, nil
+	case tasksChangedMsg:
+		return m.reloadTasks(), waitForChange(m.changes)
+	case editorFinishedMsg:
+		return m.finishEditor(msg)
 	case tea.KeyMsg:
-		if msg.Type == tea.KeyCtrlC || msg.String() == "q" {
+		// plain q only quits from the board; in prompts it is text
+		if msg.Type == tea.KeyCtrlC || (msg.String() == "q" && m.mode == modeList) {
 			return m, tea.Quit
 		}
+		m.notice = ""
 		switch m.mode {
 		case modeList:
 			return m.updateListMode(msg)
 		case modeNew, modeEdit:
 			return m.updateInputMode(msg)
+		case modeBoards:
+			return m.updateBoardMode(msg)
+		case modeTrash:
+			return m.updateTrashMode(msg)
+		case modeTags:
+			return m.updateTagMode(msg)
+		case modeSearch:
+			return m.updateSearchMode(msg)
+		case modeQuery:
+			return m.updateQueryMode(msg)
+		case modeDetail:
+			return m.updateDetailMode(msg)
 		}
 	}
 	return m, nil
@@ -29,13 +57,26 @@ func (m Model) updateListMode(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 	col := m.focused
 	items := m.tasksByStatus[col]
 	cur := m.selectedIdx[col]
+	if len(m.visibleOrder(col)) == 0 {
+		// everything in the column is filtered out
+		items = nil
+	}
+
+	// while a search is active n/N walk its hits instead of adding tasks
+	if m.searchQuery() != "" && (key.String() == "n" || key.String() == "N") {
+		dir := 1
+		if key.String() == "N" {
+			dir = -1
+		}
+		return m.jumpHit(dir), nil
+	}
 
 	switch key.String() {
 	case "up", "k":
 		if len(items) == 0 {
 			return m, nil
 		}
-		order := task.SortedOrder(items)
+		order := m.visibleOrder(col)
 		pos := indexOf(order, cur)
 		if pos == -1 {
 			pos = 0
@@ -48,7 +89,7 @@ func (m Model) updateListMode(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if len(items) == 0 {
 			return m, nil
 		}
-		order := task.SortedOrder(items)
+		order := m.visibleOrder(col)
 		pos := indexOf(order, cur)
 		if pos == -1 {
 			pos = 0
@@ -62,10 +103,19 @@ func (m Model) updateListMode(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "right", "l":
 		m.focused = nextStatus(m.focused)
 	case "[", "\\":
+		if len(items) == 0 {
+			return m, nil
+		}
 		m = m.moveTask(col, cur, prevStatus(col))
 	case "]", "/":
+		if len(items) == 0 {
+			return m, nil
+		}
 		m = m.moveTask(col, cur, nextStatus(col))
 	case " ", "x":
+		if len(items) == 0 {
+			return m, nil
+		}
 		target := domain.TaskStatusDone
 		if col == domain.TaskStatusDone {
 			target = domain.TaskStatusTodo
@@ -78,6 +128,28 @@ func (m Model) updateListMode(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 		it := items[cur]
 		_ = m.svc.ToggleStar(it.Id)
 		m.tasksByStatus[col][cur].IsStarred = !m.tasksByStatus[col][cur].IsStarred
+	case "+", "=", "-":
+		if len(items) == 0 {
+			return m, nil
+		}
+		delta := 1
+		if key.String() == "-" {
+			delta = -1
+		}
+		t, err := m.svc.ShiftPriority(items[cur].Id, delta)
+		if err != nil {
+			m.notice = err.Error()
+			return m, nil
+		}
+		m.tasksByStatus[col][cur] = t
+		m.notice = "priority: " + task.PriorityLabel(t.Priority)
+	case "enter":
+		return m.toggleDetail()
+	case "tab":
+		if len(items) == 0 {
+			return m, nil
+		}
+		return m.focusDetail()
 	case "n":
 		m.mode = modeNew
 		m.input.SetValue("")
@@ -89,10 +161,15 @@ func (m Model) updateListMode(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.mode = modeEdit
 		m.editingRef = &taskRef{status: col, index: cur}
-		m.input.SetValue(items[cur].Content)
+		m.input.SetValue(task.EditText(items[cur]))
 		m.input.CursorEnd()
 		m.input.Focus()
 		return m, textBlink()
+	case "E":
+		if len(items) == 0 {
+			return m, nil
+		}
+		return m.openEditor(items[cur])
 	case "backspace", "delete", "d":
 		if len(items) == 0 {
 			return m, nil
@@ -102,17 +179,50 @@ func (m Model) updateListMode(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.deleteTask(col, cur)
 	case "g":
 		if len(items) > 0 {
-			order := task.SortedOrder(items)
+			order := m.visibleOrder(col)
 			m.selectedIdx[col] = order[0]
 		}
 	case "G":
 		if len(items) > 0 {
-			order := task.SortedOrder(items)
+			order := m.visibleOrder(col)
 			m.selectedIdx[col] = order[len(order)-1]
 		}
+	case "u":
+		m = m.undo(false)
+	case "ctrl+r":
+		m = m.undo(true)
+	case "t":
+		return m.openTrash()
+	case "b":
+		return m.openBoardPicker(boardSwitch)
+	case "m":
+		return m.openBoardPicker(boardMoveTask)
+	case "K", "J":
+		if len(items) == 0 {
+			return m, nil
+		}
+		m = m.reorder(col, cur, key.String() == "J")
+	case "o":
+		m.sortMode = nextSortMode(m.sortMode)
+		_ = m.svc.SetSortMode(m.sortMode)
+		m.notice = "sort: " + sortModeLabel(m.sortMode)
+	case "#":
+		return m.openTagFilter()
+	case "f":
+		return m.openSearch()
+	case "F":
+		return m.openQueryPrompt()
+	case "esc":
+		if m.filtering() {
+			m.search.SetValue("")
+			m = m.setQueryFilter(nil, "")
+			m = m.setTagFilter(nil)
+			m.notice = "filter cleared"
+		}
 	case "v":
 		m.vertical = !m.vertical
 		_ = m.svc.SetLayoutVertical(m.vertical)
 	}
+	m.syncSelection()
 	return m, nil
 }
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m Model) openTrash() (tea.Model, tea.Cmd) {
+	m.mode = modeTrash
+	m.trashCursor = 0
+	return m, nil
+}
+
+func (m Model) updateTrashMode(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	trash := m.svc.Trash()
+	switch key.String() {
+	case "esc", "t":
+		m.mode = modeList
+	case "up", "k":
+		if m.trashCursor > 0 {
+			m.trashCursor--
+		}
+	case "down", "j":
+		if m.trashCursor < len(trash)-1 {
+			m.trashCursor++
+		}
+	case "g":
+		m.trashCursor = 0
+	case "G":
+		m.trashCursor = max(0, len(trash)-1)
+	case "r", "enter":
+		if len(trash) == 0 {
+			return m, nil
+		}
+		t, err := m.svc.Restore(trash[m.trashCursor].Id)
+		if err != nil {
+			m.notice = err.Error()
+			return m, nil
+		}
+		m = m.setTasks(m.svc.Tasks())
+		m.notice = fmt.Sprintf("restored to %s: %s", statusTitle(t.Status), t.Content)
+	case "d", "x", "delete", "backspace":
+		if len(trash) == 0 {
+			return m, nil
+		}
+		t := trash[m.trashCursor]
+		if err := m.svc.Purge(t.Id); err != nil {
+			m.notice = err.Error()
+			return m, nil
+		}
+		m.notice = "purged: " + t.Content
+	case "D":
+		n, err := m.svc.EmptyTrash()
+		if err != nil {
+			m.notice = err.Error()
+			return m, nil
+		}
+		m.notice = fmt.Sprintf("emptied trash (%d tasks)", n)
+	case "u":
+		m = m.undo(false)
+	case "ctrl+r":
+		m = m.undo(true)
+	}
+	if n := len(m.svc.Trash()); m.trashCursor >= n {
+		m.trashCursor = max(0, n-1)
+	}
+	return m, nil
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/hungtrd/lazytodo/internal/domain"
@@ -12,19 +13,23 @@ import (
 func (m Model) View() string {
 	// Layout
 	totalWidth := max(30, m.width)
+	boardWidth, paneWidth := m.paneWidths(totalWidth)
 	sections := make([]string, 0, len(statusOrder))
 	frameW, _ := columnStyle.GetFrameSize()
 	gapW := 0
 	if !m.vertical {
 		gapW = 1
 	}
+	// horizontal padding of a column, which item text cannot use
+	padW := columnStyle.GetHorizontalPadding()
+	rows := m.itemRows()
 
 	if m.vertical {
-		contentW := max(1, totalWidth-frameW)
+		contentW := max(1, boardWidth-frameW)
 		for _, st := range statusOrder {
 			title := statusTitle(st)
-			items := m.renderItems(st)
-			header := headerStyle.Render(fmt.Sprintf("%s (%d)", title, len(m.tasksByStatus[st])))
+			items := m.scrollItems(st, m.renderItems(st), rows[st], contentW-padW)
+			header := headerStyle.Render(fmt.Sprintf("%s (%s)", title, m.columnCount(st)))
 			content := header + "\n" + strings.Join(items, "\n")
 			style := unfocusedColStyle
 			if m.focused == st {
@@ -34,7 +39,7 @@ func (m Model) View() string {
 		}
 	} else {
 		numCols := len(statusOrder)
-		contentTotal := totalWidth - numCols*frameW - (numCols-1)*gapW
+		contentTotal := boardWidth - numCols*frameW - (numCols-1)*gapW
 		if contentTotal < numCols {
 			contentTotal = numCols
 		}
@@ -51,8 +56,8 @@ func (m Model) View() string {
 		}
 		for i, st := range statusOrder {
 			title := statusTitle(st)
-			items := m.renderItems(st)
-			header := headerStyle.Render(fmt.Sprintf("%s (%d)", title, len(m.tasksByStatus[st])))
+			items := m.scrollItems(st, m.renderItems(st), rows[st], widths[i]-padW)
+			header := headerStyle.Render(fmt.Sprintf("%s (%s)", title, m.columnCount(st)))
 			contents[i] = header + "\n" + strings.Join(items, "\n")
 			style := unfocusedColStyle
 			if m.focused == st {
@@ -63,7 +68,7 @@ func (m Model) View() string {
 		}
 		gap := lipgloss.NewStyle().Width(gapW).Render(" ")
 		boardCandidate := lipgloss.JoinHorizontal(lipgloss.Top, interleave(sections, gap)...)
-		diff := totalWidth - lipgloss.Width(boardCandidate)
+		diff := boardWidth - lipgloss.Width(boardCandidate)
 		if diff != 0 {
 			last := numCols - 1
 			newW := max(1, widths[last]+diff)
@@ -77,23 +82,123 @@ func (m Model) View() string {
 		gap := lipgloss.NewStyle().Width(gapW).Render(" ")
 		board = lipgloss.JoinHorizontal(lipgloss.Top, interleave(sections, gap)...)
 	}
+	if paneWidth > 0 {
+		// the pane is as tall as the area between the title and the footer
+		height := -1
+		if m.height > 0 {
+			height = m.height - 1 - lipgloss.Height(m.renderFooter(totalWidth))
+		}
+		pane := m.renderDetail(paneWidth, height)
+		if boardWidth == 0 {
+			board = pane
+		} else {
+			board = lipgloss.JoinHorizontal(lipgloss.Top, board, " ", pane)
+		}
+	}
 
-	help := m.renderHelp(totalWidth)
+	if m.mode == modeBoards && m.picker != nil {
+		board = m.renderBoardPicker(totalWidth)
+	}
+	if m.mode == modeTrash {
+		board = m.renderTrash(totalWidth)
+	}
+	if m.mode == modeTags && m.tagPicker != nil {
+		board = m.renderTagPicker(totalWidth)
+	}
+	if m.mode == modeQuery && m.queryPicker != nil {
+		board = m.renderQueryPrompt(totalWidth)
+	}
+	board = m.renderTitle() + "\n" + board
+	return board + "\n" + m.renderFooter(totalWidth)
+}
 
-	if m.mode == modeNew {
-		prompt := footerStyle.Copy().Bold(true).Render("New Task:")
-		return board + "\n" + prompt + "\n" + m.input.View() + "\n" + help
+// renderTitle shows the app name and the active board
+func (m Model) renderTitle() string {
+	title := titleStyle.Render("lazytodo")
+	if name := m.svc.BoardName(); name != "" {
+		title += boardNameStyle.Render(" · " + name)
+	}
+	if m.sortMode != task.SortDefault {
+		title += boardNameStyle.Render(" · sort: " + sortModeLabel(m.sortMode))
 	}
-	if m.mode == modeEdit {
-		prompt := footerStyle.Copy().Bold(true).Render("Edit Task:")
-		return board + "\n" + prompt + "\n" + m.input.View() + "\n" + help
+	if len(m.tagFilter) > 0 {
+		title += boardNameStyle.Render(" · " + m.tagFilterLabel())
 	}
-	return board + "\n" + help
+	if m.filter != nil {
+		title += boardNameStyle.Render(" · " + m.queryFilterLabel())
+	}
+	if q := m.searchQuery(); q != "" && m.mode != modeSearch {
+		title += boardNameStyle.Render(fmt.Sprintf(" · search %q (n/N: next/prev)", q))
+	}
+	return title
+}
+
+// columnCount is the task count shown in a column header; while filtering
+// it reads "shown/total".
+func (m Model) columnCount(status domain.TaskStatus) string {
+	total := len(m.tasksByStatus[status])
+	if !m.filtering() {
+		return fmt.Sprint(total)
+	}
+	return fmt.Sprintf("%d/%d", len(m.visibleOrder(status)), total)
+}
+
+// renderTrash lists deleted tasks in place of the columns
+func (m Model) renderTrash(totalWidth int) string {
+	trash := m.svc.Trash()
+	lines := []string{headerStyle.Render(fmt.Sprintf("Trash (%d)", len(trash)))}
+	if len(trash) == 0 {
+		lines = append(lines, mutedStyle.Render("  trash is empty"))
+	}
+	for i, t := range trash {
+		when := time.Unix(t.DeletedAt, 0).Format("2006-01-02 15:04")
+		meta := mutedStyle.Render(fmt.Sprintf("  %s · from %s", when, statusTitle(t.TrashedFrom)))
+		if i == m.trashCursor {
+			lines = append(lines, cursorBullet+" "+selectedTextStyle.Render(t.Content)+meta)
+		} else {
+			lines = append(lines, "  "+t.Content+meta)
+		}
+	}
+	lines = append(lines, "", footerStyle.Copy().MarginTop(0).Render("r/enter: restore · d: purge · D: empty trash · u: undo · esc/t: back"))
+	frameW, _ := columnStyle.GetFrameSize()
+	return focusedColStyle.Width(max(1, totalWidth-frameW)).Render(strings.Join(lines, "\n"))
+}
+
+// renderBoardPicker draws the board list in place of the columns
+func (m Model) renderBoardPicker(totalWidth int) string {
+	p := m.picker
+	title := "Switch board"
+	if p.action == boardMoveTask {
+		title = "Move task to board"
+	}
+	lines := []string{headerStyle.Render(title), p.input.View(), ""}
+	matches := p.matches()
+	for i, name := range matches {
+		label := name
+		if name == m.svc.BoardName() {
+			label += " (current)"
+		}
+		if i == p.cursor {
+			lines = append(lines, cursorBullet+" "+selectedTextStyle.Render(label))
+		} else {
+			lines = append(lines, "  "+label)
+		}
+	}
+	if typed := p.choice(); len(matches) == 0 && typed != "" {
+		lines = append(lines, "  "+starredStyle.Render("+ create \""+typed+"\""))
+	}
+	if p.err != "" {
+		lines = append(lines, "", errorStyle.Render(p.err))
+	}
+	frameW, _ := columnStyle.GetFrameSize()
+	w := min(max(30, totalWidth/2), totalWidth) - frameW
+	box := focusedColStyle.Width(max(1, w)).Render(strings.Join(lines, "\n"))
+	return lipgloss.PlaceHorizontal(totalWidth, lipgloss.Center, box)
 }
 
 func (m Model) renderItems(status domain.TaskStatus) []string {
 	list := append([]domain.Task(nil), m.tasksByStatus[status]...)
-	order := task.SortedOrder(list)
+	order := m.visibleOrder(status)
 
 	indexInOriginal := func(tk domain.Task) int {
 		for i, t := range m.tasksByStatus[status] {
@@ -104,6 +209,7 @@ func (m Model) renderItems(status domain.TaskStatus) []string {
 		return -1
 	}
 
+	now := time.Now()
 	lines := make([]string, 0, len(order))
 	for _, ordIdx := range order {
 		t := list[ordIdx]
@@ -111,8 +217,13 @@ func (m Model) renderItems(status domain.TaskStatus) []string {
 		if t.IsStarred {
 			star = starredStyle.Render("★ ")
 		}
+		star = priorityMarker(t.Priority) + star
 		baseText := t.Content
-		isSelected := indexInOriginal(t) == m.selectedIdx[status] && m.focused == status && m.mode == modeList
+		isSelected := indexInOriginal(t) == m.selectedIdx[status] && m.focused == status && (m.mode == modeList || m.mode == modeSearch || m.mode == modeDetail)
+		hit := searchHit{tag: -1}
+		if m.searchQuery() != "" {
+			hit, _ = m.matchSearch(t)
+		}
 
 		var textStyled string
 		if isSelected {
@@ -120,10 +231,12 @@ func (m Model) renderItems(status domain.TaskStatus) []string {
 			if status == domain.TaskStatusDone {
 				style = style.Copy().Strikethrough(true)
 			}
-			textStyled = style.Render(baseText)
+			textStyled = highlight(baseText, hit.content, style)
 		} else {
 			if status == domain.TaskStatusDone {
-				textStyled = doneStyle.Render(baseText)
+				textStyled = highlight(baseText, hit.content, doneStyle)
+			} else if len(hit.content) > 0 {
+				textStyled = highlight(baseText, hit.content, lipgloss.NewStyle())
 			} else {
 				textStyled = baseText
 			}
@@ -136,6 +249,15 @@ func (m Model) renderItems(status domain.TaskStatus) []string {
 			left = "  "
 		}
 		line := left + star + textStyled
+		if progress := renderProgress(t); progress != "" {
+			line += " " + progress
+		}
+		if len(t.Tags) > 0 {
+			line += " " + m.renderTags(t.Tags, hit)
+		}
+		if badge := dueBadge(t, now); badge != "" {
+			line += " " + badge
+		}
 		lines = append(lines, line)
 	}
 	return lines
@@ -150,13 +272,26 @@ func (m Model) renderHelp(totalWidth int) string {
 		"[ \\ / ]: move task",
 		"space/x: toggle done",
 		"s: star",
+		"+/-: priority",
+		"u/ctrl+r: undo/redo",
 		"n: new",
 		"e: edit",
+		"E: edit in $EDITOR",
+		"enter: detail pane",
+		"tab: focus pane",
 		"d/backspace/del: delete",
+		"t: trash",
+		"#: filter by tags",
+		"f: search · n/N: hits",
+		"F: filter/saved views",
+		"o: sort (auto/due/manual)",
+		"K/J: reorder (manual)",
 		"v: toggle layout",
-		"q: quit",
-		"esc: cancel",
 	}
+	if m.svc.HasBoards() {
+		items = append(items, "b: boards", "m: move to board")
+	}
+	items = append(items, "q: quit", "esc: cancel")
 
 	minColWidth := 22
 	gapW := 2
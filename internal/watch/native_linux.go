@@ -0,0 +1,86 @@
+//go:build linux
+
+package watch
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// startNative watches the parent directory rather than the file itself, so
+// the watch survives the file being replaced by a rename.
+func startNative(path string, notify func()) (func(), error) {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create watch dir: %w", err)
+	}
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify init: %w", err)
+	}
+	// IN_MODIFY covers files rewritten in place, such as a SQLite database
+	const mask = unix.IN_MODIFY | unix.IN_CLOSE_WRITE | unix.IN_MOVED_TO | unix.IN_MOVED_FROM |
+		unix.IN_CREATE | unix.IN_DELETE | unix.IN_DELETE_SELF | unix.IN_MOVE_SELF
+	if _, err := unix.InotifyAddWatch(fd, dir, mask); err != nil {
+		_ = unix.Close(fd)
+		return nil, fmt.Errorf("inotify add watch: %w", err)
+	}
+	// a non-blocking fd wrapped in *os.File goes through the runtime poller,
+	// so Close unblocks the pending Read
+	f := os.NewFile(uintptr(fd), "inotify")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+		for {
+			n, err := f.Read(buf)
+			if err != nil {
+				return
+			}
+			if matchesName(buf[:n], name) {
+				notify()
+			}
+		}
+	}()
+	return func() {
+		_ = f.Close()
+		wg.Wait()
+	}, nil
+}
+
+// matchesName reports whether any event in buf concerns name, or the watched
+// directory itself.
+func matchesName(buf []byte, name string) bool {
+	for off := 0; off+unix.SizeofInotifyEvent <= len(buf); {
+		ev := (*unix.InotifyEvent)(unsafe.Pointer(&buf[off]))
+		nameStart := off + unix.SizeofInotifyEvent
+		nameEnd := nameStart + int(ev.Len)
+		if nameEnd > len(buf) {
+			return false
+		}
+		if ev.Mask&(unix.IN_DELETE_SELF|unix.IN_MOVE_SELF) != 0 {
+			return true
+		}
+		// names are NUL padded to an alignment boundary
+		evName := buf[nameStart:nameEnd]
+		if i := bytes.IndexByte(evName, 0); i >= 0 {
+			evName = evName[:i]
+		}
+		if string(evName) == name {
+			return true
+		}
+		off = nameEnd
+	}
+	return false
+}
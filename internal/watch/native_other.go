@@ -0,0 +1,9 @@
+//go:build !linux
+
+package watch
+
+import "errors"
+
+func startNative(path string, notify func()) (func(), error) {
+	return nil, errors.New("native file watching not supported on this platform")
+}
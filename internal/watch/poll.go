@@ -0,0 +1,51 @@
+package watch
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func statFile(path string) fileState {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, size: fi.Size(), modTime: fi.ModTime()}
+}
+
+// startPolling compares the file's state every interval and calls notify
+// when it differs. The returned stop func waits for the goroutine to exit.
+func startPolling(path string, interval time.Duration, notify func()) func() {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		last := statFile(path)
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				cur := statFile(path)
+				if cur != last {
+					last = cur
+					notify()
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
@@ -0,0 +1,73 @@
+// Package watch notifies about changes to a single file. It uses inotify on
+// Linux and falls back to polling the file's size and modification time
+// elsewhere, or when inotify is unavailable.
+package watch
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval is how often the polling fallback checks the file.
+const DefaultPollInterval = time.Second
+
+// Watcher reports changes to one file on its Events channel. Bursts of
+// changes are coalesced: a receiver that is busy sees a single event.
+type Watcher struct {
+	events chan struct{}
+
+	mu     sync.Mutex
+	stop   func()
+	closed bool
+}
+
+// New starts watching path. The file does not have to exist yet; creating,
+// replacing (e.g. by an atomic rename), writing and removing it all count as
+// changes.
+func New(path string) *Watcher {
+	w := &Watcher{events: make(chan struct{}, 1)}
+	w.stop = w.start(path)
+	return w
+}
+
+// SetPath switches the watcher to another file, keeping the Events channel.
+func (w *Watcher) SetPath(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	w.stop()
+	w.stop = w.start(path)
+}
+
+func (w *Watcher) start(path string) func() {
+	stop, err := startNative(path, w.notify)
+	if err != nil {
+		stop = startPolling(path, DefaultPollInterval, w.notify)
+	}
+	return stop
+}
+
+// Events delivers a value after the file changed. It is closed by Close.
+func (w *Watcher) Events() <-chan struct{} { return w.events }
+
+// Close stops watching and closes the Events channel.
+func (w *Watcher) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	w.stop()
+	close(w.events)
+	return nil
+}
+
+func (w *Watcher) notify() {
+	select {
+	case w.events <- struct{}{}:
+	default:
+	}
+}